@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+
+	"fyne.io/fyne/v2/widget"
+
+	"myapp/engine"
+)
+
+// formatRemoteVersion 描述服务器上的版本：优先使用版本清单公布的版本号，没有公布时用发布时间。
+func formatRemoteVersion(state *AppState, info engine.RemotePackageInfo) string {
+	if state.latestAircraftVersion != "" {
+		return state.latestAircraftVersion
+	}
+	if t := info.Published(); !t.IsZero() {
+		return state.tr("published_on_label", t.Local().Format("2006-01-02 15:04"))
+	}
+	return state.tr("version_unknown")
+}
+
+// checkAircraftUpdate 在后台查询版本清单和服务器上的飞机包，然后刷新飞机页面的版本信息。
+// 版本清单公布了版本号且能读出已安装的版本号时比较版本号，否则与安装收据记录的 ETag/Last-Modified 比较。
+func checkAircraftUpdate(state *AppState) {
+	ctx := context.Background()
+	src := downloadURLAg330[0]
+	state.latestAircraftVersion = ""
+	if releases, err := fetchVersionManifest(ctx); err == nil {
+		if r := releases[channelAircraft]; r != nil {
+			src = releaseSource(src, r)
+			state.latestAircraftVersion = r.Version
+		}
+	}
+	info, err := engine.ProbeRemotePackage(ctx, src)
+	state.aircraftCheckDone = true
+	if err != nil {
+		state.latestAircraft = nil
+		state.aircraftUpdateAvailable = false
+		refreshAircraftVersionLabels(state)
+		return
+	}
+	state.latestAircraft = &info
+	state.aircraftUpdateAvailable = false
+	if state.isAircraftInstalled {
+		latest, installed := state.latestAircraftVersion, state.installedAircraftVersion
+		if len(versionNumbers(latest)) > 0 && len(versionNumbers(installed)) > 0 {
+			state.aircraftUpdateAvailable = compareVersions(installed, latest) < 0
+		} else if db, err := engine.LoadReceipts(); err == nil {
+			if r := db.Find(engine.AircraftPackageID, state.ag330Path); r != nil {
+				state.aircraftUpdateAvailable = !info.SameAs(r)
+			}
+		}
+	}
+	refreshAircraftVersionLabels(state)
+}
+
+func refreshAircraftVersionLabels(state *AppState) {
+	if state.installedVersionLabel == nil {
+		return
+	}
+	installed := state.installedAircraftVersion
+	if installed == "" {
+		installed = state.tr("version_unknown")
+	}
+	state.installedVersionLabel.SetText(state.tr("installed_version_label", installed))
+	latest := state.tr("version_checking")
+	if state.latestAircraft != nil {
+		latest = formatRemoteVersion(state, *state.latestAircraft)
+	} else if state.aircraftCheckDone {
+		latest = state.tr("version_unavailable")
+	}
+	state.latestVersionLabel.SetText(state.tr("latest_version_label", latest))
+	switch {
+	case state.aircraftUpdateAvailable:
+		state.updateStatusLabel.SetText(state.tr("update_available_label"))
+		state.updateStatusLabel.Show()
+		state.installAircraftBtn.SetText(state.tr("update_aircraft_button"))
+		state.installAircraftBtn.Importance = widget.HighImportance
+		state.installAircraftBtn.Refresh()
+	case state.latestAircraft != nil && state.isAircraftInstalled:
+		state.updateStatusLabel.SetText(state.tr("up_to_date_label"))
+		state.updateStatusLabel.Show()
+	default:
+		state.updateStatusLabel.Hide()
+	}
+}
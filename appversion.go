@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+
+	"myapp/engine"
+)
+
+// AppVersion 是本程序的版本号，命名规则为 日期.时间-Preview/Release。
+// 发布时用 -ldflags "-X main.AppVersion=2025.8.3.20-Preview" 覆盖。
+var AppVersion = "2025.8.3.20-Preview"
+
+// VersionManifestURL 指向仓库中的 checkv.txt，每次启动时下载并与 AppVersion 比较。
+const VersionManifestURL = "https://raw.githubusercontent.com/CCA7386/Golang-XPlane12-AeroGennis-Updater/main/checkv.txt"
+
+const (
+	channelRelease = "release"
+	channelPreview = "preview"
+	// channelAircraft 不是本程序的渠道，而是飞机包的最新版本和校验值
+	channelAircraft = "aircraft"
+)
+
+// appRelease 是版本清单中某个渠道的最新版本。
+type appRelease struct {
+	Channel string
+	Version string
+	URL     string // 为空时使用 downloadURLUpdater（aircraft 渠道为 downloadURLAg330）
+	SHA256  string
+	Size    int64
+}
+
+// parseVersionManifest 解析 checkv.txt：
+//
+//	release=2025.8.3.20-Release
+//	preview=2025.8.3.20-Preview
+//	preview.sha256=<更新程序的 SHA-256>
+//	preview.size=<字节数>
+//	preview.url=<可选的下载链接>
+//	aircraft=<飞机包的版本号>
+//	aircraft.sha256=<飞机包的 SHA-256>
+//	aircraft.size=<字节数>
+//
+// 以 # 开头的行为注释。
+func parseVersionManifest(data []byte) map[string]*appRelease {
+	releases := make(map[string]*appRelease)
+	get := func(channel string) *appRelease {
+		if releases[channel] == nil {
+			releases[channel] = &appRelease{Channel: channel}
+		}
+		return releases[channel]
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		channel, field, _ := strings.Cut(key, ".")
+		if channel != channelRelease && channel != channelPreview && channel != channelAircraft {
+			continue
+		}
+		r := get(channel)
+		switch field {
+		case "":
+			r.Version = value
+		case "sha256":
+			r.SHA256 = engine.NormalizeSHA256(value)
+		case "size":
+			r.Size, _ = strconv.ParseInt(value, 10, 64)
+		case "url":
+			r.URL = value
+		}
+	}
+	for channel, r := range releases {
+		// 飞机包只有校验值时也保留
+		if r.Version == "" && channel != channelAircraft {
+			delete(releases, channel)
+		}
+	}
+	return releases
+}
+
+// versionChannel 从版本号后缀判断渠道，没有后缀的视为正式版。
+func versionChannel(version string) string {
+	if strings.HasSuffix(strings.ToLower(version), "-preview") {
+		return channelPreview
+	}
+	return channelRelease
+}
+
+// versionNumbers 取出版本号中的数字部分，例如 "v2025.8.3.20-Preview" -> [2025 8 3 20]。
+func versionNumbers(version string) []int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexByte(version, '-'); i >= 0 {
+		version = version[:i]
+	}
+	var nums []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		nums = append(nums, n)
+	}
+	return nums
+}
+
+// compareVersions 比较两个版本号的数字部分，返回 -1、0 或 1。
+func compareVersions(a, b string) int {
+	na, nb := versionNumbers(a), versionNumbers(b)
+	for i := 0; i < max(len(na), len(nb)); i++ {
+		var x, y int
+		if i < len(na) {
+			x = na[i]
+		}
+		if i < len(nb) {
+			y = nb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// newestRelease 返回 channel 渠道可以升级到的最新版本：预览版用户也会收到更新的正式版。
+func newestRelease(releases map[string]*appRelease, channel string) *appRelease {
+	best := releases[channelRelease]
+	if channel == channelPreview {
+		if p := releases[channelPreview]; p != nil && (best == nil || compareVersions(p.Version, best.Version) > 0) {
+			best = p
+		}
+	}
+	return best
+}
+
+// releaseSource 返回要下载的包：版本清单提供了链接或哈希时优先使用，否则使用 src。
+func releaseSource(src engine.Source, release *appRelease) engine.Source {
+	if release == nil {
+		return src
+	}
+	if release.URL != "" {
+		src = engine.Source{URL: release.URL}
+	}
+	if release.SHA256 != "" {
+		src.SHA256 = release.SHA256
+	}
+	if release.Size > 0 {
+		src.Size = release.Size
+	}
+	return src
+}
+
+// fetchVersionManifest 下载并解析版本清单。
+func fetchVersionManifest(ctx context.Context) (map[string]*appRelease, error) {
+	data, err := engine.Fetch(ctx, VersionManifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseVersionManifest(data), nil
+}
+
+// aircraftSource 返回要下载的飞机包，带上版本清单中公布的 SHA-256 和大小。
+// 无法下载版本清单时返回没有校验值的包和下载清单的错误，调用者应告诉用户飞机包将无法校验。
+func aircraftSource(ctx context.Context) (engine.Source, error) {
+	releases, err := fetchVersionManifest(ctx)
+	if err != nil {
+		return downloadURLAg330[0], err
+	}
+	return releaseSource(downloadURLAg330[0], releases[channelAircraft]), nil
+}
+
+// checkAppUpdate 下载版本清单，如果有比 AppVersion 更新的版本就在“更新程序”页面显示提示。
+func checkAppUpdate(state *AppState) {
+	releases, err := fetchVersionManifest(context.Background())
+	if err != nil {
+		return
+	}
+	latest := newestRelease(releases, state.updateChannel())
+	if latest == nil || compareVersions(latest.Version, AppVersion) <= 0 {
+		state.appUpdate = nil
+	} else {
+		state.appUpdate = latest
+	}
+	refreshAppUpdateBanner(state)
+}
+
+// updateChannel 返回用户所在的更新渠道：配置文件中指定了渠道时以配置为准，否则跟随本程序版本号。
+func (state *AppState) updateChannel() string {
+	if state.config != nil {
+		switch channel := strings.ToLower(state.config.UpdateChannel); channel {
+		case channelRelease, channelPreview:
+			return channel
+		}
+	}
+	return versionChannel(AppVersion)
+}
+
+func refreshAppUpdateBanner(state *AppState) {
+	if state.appUpdateBanner == nil {
+		return
+	}
+	if state.appUpdate == nil {
+		state.appUpdateBanner.Hide()
+		return
+	}
+	state.appUpdateBanner.SetText(state.tr("app_update_available_banner", state.appUpdate.Version, AppVersion))
+	state.appUpdateBanner.Show()
+}
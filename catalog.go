@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"myapp/engine"
+)
+
+// 涂装目录 (LiveriesList.json) 格式：
+//
+//	{
+//	  "schema_version": 3,
+//	  "liveries": [
+//	    {
+//	      "id": "cca-b-5948",
+//	      "name": "Air China B-5948",
+//	      "airline": "Air China",
+//	      "icao": "CCA",
+//	      "registration": "B-5948",
+//	      "version": "2",
+//	      "real": true,
+//	      "author": "...",
+//	      "url": "https://files.zohopublic.com.cn/...",
+//	      "size": 123456789,
+//	      "sha256": "...",
+//	      "preview": "https://.../preview.png",
+//	      "min_aircraft_version": "2025.7.26"
+//	    }
+//	  ]
+//	}
+//
+// 旧的 LiveriesList.txt（名称行与链接行交替）仍可读取。
+// schema_version 2 增加了可选的 icao（航空公司的三字 ICAO 代码），schema_version 3 增加了可选的 version
+// （涂装的版本，变化时已安装的涂装显示为过期），旧版本的目录仍可读取。
+const catalogSchemaVersion = 3
+
+const (
+	catalogFileName       = "LiveriesList.json"
+	legacyCatalogFileName = "LiveriesList.txt"
+)
+
+type catalogEntry struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Airline            string `json:"airline"`
+	ICAO               string `json:"icao,omitempty"`
+	Registration       string `json:"registration"`
+	Version            string `json:"version,omitempty"`
+	Real               *bool  `json:"real"`
+	Author             string `json:"author,omitempty"`
+	URL                string `json:"url"`
+	Size               int64  `json:"size,omitempty"`
+	SHA256             string `json:"sha256,omitempty"`
+	Preview            string `json:"preview,omitempty"`
+	MinAircraftVersion string `json:"min_aircraft_version,omitempty"`
+}
+
+// catalogIssue 是目录中的一个问题，Line 从 1 开始。
+type catalogIssue struct {
+	Line int
+	Msg  string
+}
+
+// catalogError 汇总目录解析或校验时发现的全部问题。
+type catalogError struct {
+	Issues []catalogIssue
+}
+
+func (e *catalogError) Error() string {
+	lines := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		lines = append(lines, fmt.Sprintf("第 %d 行: %s", issue.Line, issue.Msg))
+	}
+	return "涂装目录无效:\n" + strings.Join(lines, "\n")
+}
+
+var (
+	catalogIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	sha256Pattern    = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	icaoPattern      = regexp.MustCompile(`^[A-Z]{3}$`)
+	nonSlugPattern   = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// isJSONCatalog 判断数据是否为 JSON 目录，否则按旧格式处理。
+func isJSONCatalog(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n\ufeff")
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// parseCatalog 根据内容选择 JSON 或旧格式解析器。
+func parseCatalog(data []byte) ([]Livery, error) {
+	if isJSONCatalog(data) {
+		return parseCatalogJSON(data)
+	}
+	return parseLegacyCatalog(bytes.NewReader(data))
+}
+
+// lineAt 返回 offset 之后第一个有效字符所在的行号。
+func lineAt(data []byte, offset int64) int {
+	i := int(min(offset, int64(len(data))))
+	for i < len(data) && strings.IndexByte(" \t\r\n,", data[i]) >= 0 {
+		i++
+	}
+	return bytes.Count(data[:i], []byte("\n")) + 1
+}
+
+// decodeErrorLine 尽可能从 JSON 解码错误中得到准确的行号。
+func decodeErrorLine(data []byte, err error, fallback int) int {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return bytes.Count(data[:min(int(syntaxErr.Offset), len(data))], []byte("\n")) + 1
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return bytes.Count(data[:min(int(typeErr.Offset), len(data))], []byte("\n")) + 1
+	}
+	return fallback
+}
+
+func expectDelim(dec *json.Decoder, data []byte, want json.Delim) error {
+	offset := dec.InputOffset()
+	tok, err := dec.Token()
+	if err != nil {
+		return &catalogError{Issues: []catalogIssue{{Line: decodeErrorLine(data, err, lineAt(data, offset)), Msg: err.Error()}}}
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return &catalogError{Issues: []catalogIssue{{Line: lineAt(data, offset), Msg: fmt.Sprintf("应为 '%s'", want)}}}
+	}
+	return nil
+}
+
+// parseCatalogJSON 严格解析 JSON 目录：未知字段、缺少必填项、重复 id 都会报错，并给出行号。
+func parseCatalogJSON(data []byte) ([]Livery, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := expectDelim(dec, data, '{'); err != nil {
+		return nil, err
+	}
+
+	var issues []catalogIssue
+	schemaVersion, schemaLine := 0, 1
+	var entries []catalogEntry
+	var entryLines []int
+	seenLiveries := false
+	for dec.More() {
+		keyOffset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, &catalogError{Issues: []catalogIssue{{Line: decodeErrorLine(data, err, lineAt(data, keyOffset)), Msg: err.Error()}}}
+		}
+		key, _ := tok.(string)
+		keyLine := lineAt(data, keyOffset)
+		switch key {
+		case "schema_version":
+			schemaLine = keyLine
+			if err := dec.Decode(&schemaVersion); err != nil {
+				return nil, &catalogError{Issues: []catalogIssue{{Line: decodeErrorLine(data, err, keyLine), Msg: err.Error()}}}
+			}
+		case "liveries":
+			seenLiveries = true
+			if err := expectDelim(dec, data, '['); err != nil {
+				return nil, err
+			}
+			for dec.More() {
+				entryLine := lineAt(data, dec.InputOffset())
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err != nil {
+					return nil, &catalogError{Issues: []catalogIssue{{Line: decodeErrorLine(data, err, entryLine), Msg: err.Error()}}}
+				}
+				// 逐条严格解码，一条出错时继续检查其余条目
+				var entry catalogEntry
+				strict := json.NewDecoder(bytes.NewReader(raw))
+				strict.DisallowUnknownFields()
+				if err := strict.Decode(&entry); err != nil {
+					issues = append(issues, catalogIssue{Line: entryLine + decodeErrorLine(raw, err, 1) - 1, Msg: err.Error()})
+					continue
+				}
+				entries = append(entries, entry)
+				entryLines = append(entryLines, entryLine)
+			}
+			if err := expectDelim(dec, data, ']'); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, &catalogError{Issues: []catalogIssue{{Line: keyLine, Msg: fmt.Sprintf("未知字段 %q", key)}}}
+		}
+	}
+	if err := expectDelim(dec, data, '}'); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, &catalogError{Issues: []catalogIssue{{Line: lineAt(data, dec.InputOffset()), Msg: "目录结束后还有多余内容"}}}
+	}
+
+	if schemaVersion == 0 {
+		issues = append(issues, catalogIssue{Line: schemaLine, Msg: "缺少 schema_version"})
+	} else if schemaVersion > catalogSchemaVersion {
+		issues = append(issues, catalogIssue{Line: schemaLine, Msg: fmt.Sprintf("不支持的 schema_version %d，请更新本程序", schemaVersion)})
+	}
+	if !seenLiveries {
+		issues = append(issues, catalogIssue{Line: 1, Msg: "缺少 liveries"})
+	}
+
+	liveries := make([]Livery, 0, len(entries))
+	seenIDs := make(map[string]int)
+	for i, entry := range entries {
+		line := entryLines[i]
+		for _, msg := range validateCatalogEntry(entry) {
+			issues = append(issues, catalogIssue{Line: line, Msg: msg})
+		}
+		if first, dup := seenIDs[entry.ID]; dup && entry.ID != "" {
+			issues = append(issues, catalogIssue{Line: line, Msg: fmt.Sprintf("id %q 与第 %d 行重复", entry.ID, first)})
+		} else {
+			seenIDs[entry.ID] = line
+		}
+		liveries = append(liveries, entry.livery())
+	}
+	if len(issues) > 0 {
+		sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+		return nil, &catalogError{Issues: issues}
+	}
+	return liveries, nil
+}
+
+func validateCatalogEntry(e catalogEntry) []string {
+	var msgs []string
+	if e.ID == "" {
+		msgs = append(msgs, "缺少 id")
+	} else if !catalogIDPattern.MatchString(e.ID) {
+		msgs = append(msgs, fmt.Sprintf("id %q 只能包含小写字母、数字、'.'、'_' 和 '-'", e.ID))
+	}
+	if strings.TrimSpace(e.Name) == "" {
+		msgs = append(msgs, "缺少 name")
+	}
+	if e.Real == nil {
+		msgs = append(msgs, "缺少 real")
+	}
+	if e.ICAO != "" && !icaoPattern.MatchString(e.ICAO) {
+		msgs = append(msgs, fmt.Sprintf("icao %q 必须是三个大写字母", e.ICAO))
+	}
+	if e.URL == "" {
+		msgs = append(msgs, "缺少 url")
+	} else if !strings.HasPrefix(e.URL, "https://") {
+		msgs = append(msgs, fmt.Sprintf("url %q 必须以 https:// 开头", e.URL))
+	}
+	if e.Size < 0 {
+		msgs = append(msgs, "size 不能为负数")
+	}
+	if e.SHA256 != "" && !sha256Pattern.MatchString(engine.NormalizeSHA256(e.SHA256)) {
+		msgs = append(msgs, "sha256 必须是 64 位十六进制字符串")
+	}
+	if e.Preview != "" && !strings.HasPrefix(e.Preview, "https://") {
+		msgs = append(msgs, fmt.Sprintf("preview %q 必须以 https:// 开头", e.Preview))
+	}
+	return msgs
+}
+
+func (e catalogEntry) livery() Livery {
+	l := Livery{
+		ID:                 e.ID,
+		Name:               strings.TrimSpace(e.Name),
+		Airline:            e.Airline,
+		ICAO:               e.ICAO,
+		Registration:       e.Registration,
+		Version:            strings.TrimSpace(e.Version),
+		Author:             e.Author,
+		URL:                e.URL,
+		Size:               e.Size,
+		SHA256:             engine.NormalizeSHA256(e.SHA256),
+		Preview:            e.Preview,
+		MinAircraftVersion: e.MinAircraftVersion,
+	}
+	if e.Real != nil {
+		l.Real = *e.Real
+	}
+	return l
+}
+
+// parseLegacyCatalog 读取旧的 LiveriesList.txt：每个涂装是名称行和随后以 http 开头的链接行，
+// 两行之间可以有空行或以 # 或 // 开头的注释行。缺少名称或链接的行都会报告，并给出行号。
+func parseLegacyCatalog(r io.Reader) ([]Livery, error) {
+	var liveries []Livery
+	var issues []catalogIssue
+	usedIDs := make(map[string]bool)
+	var name string
+	nameLine := 0 // 还没有配上链接的名称行，0 表示没有
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, `"`) && strings.HasSuffix(line, `",`) {
+			line = line[1 : len(line)-2]
+		} else if strings.HasPrefix(line, `"`) && strings.HasSuffix(line, `"`) {
+			line = line[1 : len(line)-1]
+		}
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//"):
+		case !strings.HasPrefix(line, "http"):
+			if nameLine > 0 {
+				issues = append(issues, catalogIssue{Line: nameLine, Msg: fmt.Sprintf("涂装 %q 后面缺少下载链接", name)})
+			}
+			name, nameLine = line, lineNo
+		case nameLine == 0:
+			issues = append(issues, catalogIssue{Line: lineNo, Msg: "下载链接前面缺少涂装名称"})
+		default:
+			// 链接可以带 "#sha256=...&size=..." 片段用于完整性校验
+			src := engine.ParseSourceFragment(line)
+			liveries = append(liveries, Livery{
+				ID:     legacyLiveryID(name, src.URL, usedIDs),
+				Name:   name,
+				URL:    src.URL,
+				SHA256: src.SHA256,
+				Size:   src.Size,
+			})
+			nameLine = 0
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取 LiveriesList.txt 时出错: %w", err)
+	}
+	if nameLine > 0 {
+		issues = append(issues, catalogIssue{Line: nameLine, Msg: fmt.Sprintf("涂装 %q 后面缺少下载链接", name)})
+	}
+	if len(issues) > 0 {
+		return nil, &catalogError{Issues: issues}
+	}
+	return liveries, nil
+}
+
+// legacyLiveryID 为旧格式中没有 id 的涂装按名称生成 id，保留中文等各种文字，与涂装在列表中的位置无关。
+// 名称相同的涂装再加上链接的哈希区分；名称中没有文字时只用链接的哈希。
+func legacyLiveryID(name, url string, used map[string]bool) string {
+	hash := fmt.Sprintf("%x", sha1.Sum([]byte(url)))[:8]
+	id := strings.Trim(nonSlugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if id == "" {
+		id = "livery-" + hash
+	}
+	if used[id] {
+		id += "-" + hash
+	}
+	used[id] = true
+	return id
+}
+
+// requiresNewerAircraft 判断涂装要求的最低飞机版本是否高于已安装的版本。
+// 已安装的版本未知或无法比较时不阻止安装。
+func (l Livery) requiresNewerAircraft(installed string) bool {
+	if l.MinAircraftVersion == "" || len(versionNumbers(installed)) == 0 {
+		return false
+	}
+	return compareVersions(installed, l.MinAircraftVersion) < 0
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestLegacyCatalogSkipsBlankAndCommentLines 检查旧格式中名称行和链接行之间的空行和注释行不影响配对。
+func TestLegacyCatalogSkipsBlankAndCommentLines(t *testing.T) {
+	txt := strings.Join([]string{
+		`"Air China",`,
+		``,
+		`"https://example.com/air-china.zip",`,
+		`# 2024 年新涂装`,
+		`"China Eastern",`,
+		`// 备用链接见论坛`,
+		``,
+		`"https://example.com/china-eastern.zip",`,
+	}, "\n")
+	liveries, err := parseLegacyCatalog(strings.NewReader(txt))
+	if err != nil {
+		t.Fatalf("parseLegacyCatalog: %v", err)
+	}
+	want := map[string]string{
+		"Air China":     "https://example.com/air-china.zip",
+		"China Eastern": "https://example.com/china-eastern.zip",
+	}
+	if len(liveries) != len(want) {
+		t.Fatalf("got %d liveries, want %d: %+v", len(liveries), len(want), liveries)
+	}
+	for _, l := range liveries {
+		if want[l.Name] != l.URL {
+			t.Errorf("livery %q has URL %q, want %q", l.Name, l.URL, want[l.Name])
+		}
+	}
+}
+
+// TestLegacyCatalogReportsMissingURL 检查缺少链接的名称行按行号报告。
+func TestLegacyCatalogReportsMissingURL(t *testing.T) {
+	txt := "\"Air China\",\n\n\"China Eastern\",\n\"https://example.com/china-eastern.zip\",\n"
+	_, err := parseLegacyCatalog(strings.NewReader(txt))
+	var catErr *catalogError
+	if !errors.As(err, &catErr) || len(catErr.Issues) != 1 || catErr.Issues[0].Line != 1 {
+		t.Fatalf("parseLegacyCatalog = %v, want one issue on line 1", err)
+	}
+}
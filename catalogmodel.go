@@ -0,0 +1,216 @@
+package main
+
+import (
+	"cmp"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"myapp/engine"
+)
+
+// liveryInstalls 对照当前飞机的涂装文件夹和安装收据，返回每个目录涂装的安装情况，飞机未安装时返回空表。
+// 对照失败时返回空表和错误，所有涂装都按未安装显示。
+func liveryInstalls(state *AppState) (map[string]engine.LiveryInstall, error) {
+	if !state.isAircraftInstalled {
+		return map[string]engine.LiveryInstall{}, nil
+	}
+	pkgs := make([]engine.LiveryPackage, 0, len(state.liveries))
+	for _, l := range state.liveries {
+		pkgs = append(pkgs, l.pkg())
+	}
+	installs, err := engine.ReconcileLiveries(filepath.Join(state.ag330Path, "liveries"), pkgs)
+	if err != nil {
+		return map[string]engine.LiveryInstall{}, err
+	}
+	return installs, nil
+}
+
+// 筛选条件中的选项，零值表示不筛选。
+type realFilter int
+
+const (
+	realAny realFilter = iota
+	realOnly
+	fictionalOnly
+)
+
+type installedFilter int
+
+const (
+	installedAny installedFilter = iota
+	installedOnly
+	notInstalledOnly
+)
+
+type liverySort int
+
+const (
+	sortByName liverySort = iota
+	sortByAirline
+	sortByRegistration
+)
+
+// liveryFilter 是涂装页的搜索、筛选、分组和排序设置。
+type liveryFilter struct {
+	Query          string // 按空格分成多个词，每个词都要出现在名称、航空公司、注册号或 ICAO 代码中
+	Real           realFilter
+	Installed      installedFilter
+	UpdatesOnly    bool
+	GroupByAirline bool
+	Sort           liverySort
+}
+
+// catalogRow 是列表中的一行：分组标题（Livery 为 nil）或一个涂装。
+type catalogRow struct {
+	Header string
+	Count  int
+	Livery *Livery
+}
+
+// catalogModel 是涂装页显示的目录：在完整目录上应用筛选、排序和分组，并记住勾选的涂装。
+// 勾选按 id 保存，筛选条件变化后隐藏的涂装仍然保持勾选。
+type catalogModel struct {
+	liveries []Livery
+	installs map[string]engine.LiveryInstall
+	filter   liveryFilter
+	rows     []catalogRow
+	visible  []*Livery // 满足筛选条件的涂装，按列表顺序，画廊视图使用
+	selected map[string]bool
+}
+
+func newCatalogModel(liveries []Livery, installs map[string]engine.LiveryInstall) *catalogModel {
+	m := &catalogModel{liveries: liveries, installs: installs, selected: make(map[string]bool)}
+	m.apply()
+	return m
+}
+
+// state 返回涂装在当前飞机上的安装状态。
+func (m *catalogModel) state(id string) engine.LiveryState {
+	return m.installs[id].State
+}
+
+// outdatedLiveries 按目录顺序返回已安装但过期的涂装。
+func (m *catalogModel) outdatedLiveries() []Livery {
+	var out []Livery
+	for _, l := range m.liveries {
+		if m.state(l.ID) == engine.LiveryOutdated {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// setFilter 更换筛选条件并重新生成列表行。
+func (m *catalogModel) setFilter(f liveryFilter) {
+	m.filter = f
+	m.apply()
+}
+
+// matches 判断涂装是否满足当前的搜索和筛选条件。
+func (m *catalogModel) matches(l Livery) bool {
+	f := m.filter
+	switch {
+	case f.Real == realOnly && !l.Real, f.Real == fictionalOnly && l.Real:
+		return false
+	}
+	state := m.state(l.ID)
+	switch {
+	case f.Installed == installedOnly && state == engine.LiveryNotInstalled,
+		f.Installed == notInstalledOnly && state != engine.LiveryNotInstalled,
+		f.UpdatesOnly && state != engine.LiveryOutdated:
+		return false
+	}
+	return matchesQuery(l, f.Query)
+}
+
+// matchesQuery 判断 query 中的每个词是否都出现在涂装的名称、航空公司、注册号或 ICAO 代码中，不区分大小写。
+func matchesQuery(l Livery, query string) bool {
+	haystack := strings.ToLower(strings.Join([]string{l.Name, l.Airline, l.Registration, l.ICAO, l.Source}, "\n"))
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply 按筛选、排序和分组设置生成列表行。
+func (m *catalogModel) apply() {
+	var visible []*Livery
+	for i := range m.liveries {
+		if m.matches(m.liveries[i]) {
+			visible = append(visible, &m.liveries[i])
+		}
+	}
+	key := func(l *Livery) string {
+		switch m.filter.Sort {
+		case sortByAirline:
+			return l.Airline
+		case sortByRegistration:
+			return l.Registration
+		}
+		return l.Name
+	}
+	slices.SortStableFunc(visible, func(a, b *Livery) int {
+		if m.filter.GroupByAirline {
+			if c := cmp.Compare(strings.ToLower(a.Airline), strings.ToLower(b.Airline)); c != 0 {
+				return c
+			}
+		}
+		if c := cmp.Compare(strings.ToLower(key(a)), strings.ToLower(key(b))); c != 0 {
+			return c
+		}
+		return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	m.rows = m.rows[:0]
+	header := -1
+	for _, l := range visible {
+		if m.filter.GroupByAirline && (header < 0 || !strings.EqualFold(m.rows[header].Header, l.Airline)) {
+			m.rows = append(m.rows, catalogRow{Header: l.Airline})
+			header = len(m.rows) - 1
+		}
+		if header >= 0 {
+			m.rows[header].Count++
+		}
+		m.rows = append(m.rows, catalogRow{Livery: l})
+	}
+	m.visible = visible
+}
+
+// visibleCount 返回满足筛选条件的涂装数。
+func (m *catalogModel) visibleCount() int {
+	return len(m.visible)
+}
+
+// setSelected 勾选或取消勾选一个涂装。
+func (m *catalogModel) setSelected(id string, on bool) {
+	if on {
+		m.selected[id] = true
+	} else {
+		delete(m.selected, id)
+	}
+}
+
+// selectVisible 勾选或取消勾选当前显示的全部涂装。
+func (m *catalogModel) selectVisible(on bool) {
+	for _, l := range m.visible {
+		m.setSelected(l.ID, on)
+	}
+}
+
+func (m *catalogModel) clearSelection() {
+	clear(m.selected)
+}
+
+// selectedLiveries 按目录顺序返回勾选的涂装，包括因筛选而暂时隐藏的。
+func (m *catalogModel) selectedLiveries() []Livery {
+	var out []Livery
+	for _, l := range m.liveries {
+		if m.selected[l.ID] {
+			out = append(out, l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"myapp/engine"
+)
+
+const (
+	// catalogStateFileName 保存上次刷新官方涂装目录时服务器给出的 ETag、Last-Modified 和刷新时间。
+	catalogStateFileName = "LiveriesList.state.json"
+	// catalogBackupSuffix 是刷新前的目录备份的后缀，新目录损坏时改用备份。
+	catalogBackupSuffix = ".bak"
+	// catalogCacheDirName 是程序目录下缓存其它远程来源目录的文件夹。
+	catalogCacheDirName = "catalogs"
+	// maxDiffNames 是刷新结果中每类变化最多列出的涂装数。
+	maxDiffNames = 15
+)
+
+// catalogState 是一个远程来源上次刷新的记录。
+type catalogState struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	RefreshedAt  time.Time `json:"refreshed_at"`
+}
+
+// catalogCache 是一个远程来源的目录在程序目录下的缓存文件（相对于程序目录）。
+// 官方来源使用 LiveriesList.json 等原来的文件名，其它来源放在 catalogs 文件夹中。
+type catalogCache struct {
+	fileName       string // JSON 格式的目录
+	legacyFileName string // 旧格式的目录，与 fileName 只存在一个
+	stateFileName  string
+}
+
+var officialCatalogCache = catalogCache{catalogFileName, legacyCatalogFileName, catalogStateFileName}
+
+// loadState 读取刷新记录，没有或无法读取时返回零值（下次刷新时无条件下载）。
+func (c catalogCache) loadState() catalogState {
+	var s catalogState
+	path, err := engine.DataPath(c.stateFileName)
+	if err != nil {
+		return s
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return catalogState{}
+	}
+	return s
+}
+
+func (c catalogCache) saveState(s catalogState) error {
+	path, err := engine.DataPath(c.stateFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return engine.AtomicWriteFile(path, data, 0644)
+}
+
+// load 读取缓存的目录，目录无法解析时改用上次刷新前保存的备份，这时同时返回备份的涂装和说明改用备份的错误。
+func (c catalogCache) load() ([]Livery, error) {
+	liveries, err := c.read("")
+	if err == nil {
+		return liveries, nil
+	}
+	if backup, backupErr := c.read(catalogBackupSuffix); backupErr == nil && len(backup) > 0 {
+		return backup, fmt.Errorf("%w，改用上次的涂装目录", err)
+	}
+	return nil, err
+}
+
+// read 读取 JSON 目录（带上 suffix），不存在时读取旧格式的目录，都没有时返回空目录。
+func (c catalogCache) read(suffix string) ([]Livery, error) {
+	for _, name := range []string{c.fileName, c.legacyFileName} {
+		path, err := engine.DataPath(name + suffix)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("无法打开 %s: %w", name+suffix, err)
+		}
+		return parseCatalog(data)
+	}
+	return []Livery{}, nil
+}
+
+// replace 用已经校验过的新目录原子地替换当前目录。
+// 当前目录（无论新旧格式）先改名为备份，另一种格式的旧文件一并移走，避免读取到过期目录；写入失败时恢复原来的文件。
+func (c catalogCache) replace(data []byte) error {
+	fileName, staleName := c.legacyFileName, c.fileName
+	if isJSONCatalog(data) {
+		fileName, staleName = c.fileName, c.legacyFileName
+	}
+	path, err := engine.DataPath(fileName)
+	if err != nil {
+		return err
+	}
+	stalePath, err := engine.DataPath(staleName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	for _, p := range []string{path, stalePath} {
+		os.Remove(p + catalogBackupSuffix)
+	}
+	for _, p := range []string{path, stalePath} {
+		if err := os.Rename(p, p+catalogBackupSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := engine.AtomicWriteFile(path, data, 0644); err != nil {
+		os.Rename(path+catalogBackupSuffix, path)
+		os.Rename(stalePath+catalogBackupSuffix, stalePath)
+		return err
+	}
+	return nil
+}
+
+// catalogDiff 是两次刷新之间涂装目录的变化，按 id 对比。
+type catalogDiff struct {
+	Added   []Livery
+	Removed []Livery
+	Changed []Livery // 同一 id 的名称、链接、哈希、版本等任一项有变化
+}
+
+func (d catalogDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func diffCatalogs(old, updated []Livery) catalogDiff {
+	var d catalogDiff
+	before := make(map[string]Livery, len(old))
+	for _, l := range old {
+		before[l.ID] = l
+	}
+	seen := make(map[string]bool, len(updated))
+	for _, l := range updated {
+		seen[l.ID] = true
+		prev, ok := before[l.ID]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, l)
+		case prev != l:
+			d.Changed = append(d.Changed, l)
+		}
+	}
+	for _, l := range old {
+		if !seen[l.ID] {
+			d.Removed = append(d.Removed, l)
+		}
+	}
+	return d
+}
+
+// catalogRefresh 是一次刷新涂装目录的结果。
+type catalogRefresh struct {
+	Liveries    []Livery // 合并后的目录
+	Diff        catalogDiff
+	NotModified bool    // 所有来源都没有变化，本地目录保持不变
+	Failures    []error // 刷新失败的来源，这些来源继续使用上次的目录
+	RefreshedAt time.Time
+}
+
+// catalogRefreshText 返回刷新结果的说明：目录没有变化，或新增、删除和有变化的涂装，以及刷新失败的来源。
+func catalogRefreshText(state *AppState, r *catalogRefresh) string {
+	var sections []string
+	switch {
+	case r.NotModified:
+		sections = append(sections, state.tr("livery_list_not_modified"))
+	case r.Diff.empty():
+		sections = append(sections, state.tr("livery_list_update_success"), state.tr("catalog_diff_none"))
+	default:
+		sections = append(sections, state.tr("livery_list_update_success"))
+	}
+	for _, part := range []struct {
+		key      string
+		liveries []Livery
+	}{
+		{"catalog_diff_added", r.Diff.Added},
+		{"catalog_diff_removed", r.Diff.Removed},
+		{"catalog_diff_changed", r.Diff.Changed},
+	} {
+		if len(part.liveries) == 0 {
+			continue
+		}
+		var names []string
+		for i, l := range part.liveries {
+			if i == maxDiffNames {
+				names = append(names, state.tr("catalog_diff_more", len(part.liveries)-maxDiffNames))
+				break
+			}
+			names = append(names, l.Name)
+		}
+		sections = append(sections, state.tr(part.key, len(part.liveries))+"\n- "+strings.Join(names, "\n- "))
+	}
+	if len(r.Failures) > 0 {
+		var lines []string
+		for _, err := range r.Failures {
+			lines = append(lines, err.Error())
+		}
+		sections = append(sections, state.tr("catalog_source_failures", len(r.Failures))+"\n- "+strings.Join(lines, "\n- "))
+	}
+	return strings.Join(sections, "\n\n")
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"myapp/engine"
+)
+
+// officialSourceName 是默认的官方涂装目录来源的名称。
+const officialSourceName = "Official"
+
+// catalogSourceConfig 是一个涂装目录来源：远程的 https 链接，或本地的目录文件、存放目录文件的文件夹。
+// 所有启用的来源合并为一个目录，同一 id 的涂装使用优先级最高的来源，优先级相同时使用配置中靠前的。
+type catalogSourceConfig struct {
+	Name     string `toml:"name"`
+	Location string `toml:"location"`
+	Priority int    `toml:"priority"`
+	Enabled  *bool  `toml:"enabled,omitempty"` // 省略时启用
+}
+
+func (s catalogSourceConfig) enabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// remote 判断来源是否是需要下载的链接，否则是本地路径。
+func (s catalogSourceConfig) remote() bool {
+	return strings.HasPrefix(s.Location, "https://") || strings.HasPrefix(s.Location, "http://")
+}
+
+// insecure 判断来源是否是 http 链接。与下载涂装包一样，目录只从 https 链接下载，这样的来源读取配置时被忽略。
+func (s catalogSourceConfig) insecure() bool {
+	return strings.HasPrefix(s.Location, "http://")
+}
+
+// cache 返回远程来源的缓存文件。官方链接沿用原来的 LiveriesList.json，其它链接按链接生成固定的文件名。
+func (s catalogSourceConfig) cache() catalogCache {
+	if s.Location == LiveryListURL {
+		return officialCatalogCache
+	}
+	base := filepath.Join(catalogCacheDirName, fmt.Sprintf("%x", sha1.Sum([]byte(s.Location)))[:16])
+	return catalogCache{base + ".json", base + ".txt", base + ".state.json"}
+}
+
+// load 读取来源的目录：远程来源读取上次刷新时的缓存，本地来源直接读取文件。
+// 文件夹中的 .json 和 .txt 文件按文件名顺序读取，同一 id 使用靠前的文件中的。
+func (s catalogSourceConfig) load() ([]Livery, error) {
+	if s.remote() {
+		return s.cache().load()
+	}
+	info, err := os.Stat(s.Location)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return readCatalogFile(s.Location)
+	}
+	entries, err := os.ReadDir(s.Location)
+	if err != nil {
+		return nil, err
+	}
+	var liveries []Livery
+	seen := make(map[string]bool)
+	var errs []error
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".json" && ext != ".txt") {
+			continue
+		}
+		ls, err := readCatalogFile(filepath.Join(s.Location, e.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, l := range ls {
+			if !seen[l.ID] {
+				seen[l.ID] = true
+				liveries = append(liveries, l)
+			}
+		}
+	}
+	return liveries, errors.Join(errs...)
+}
+
+// readCatalogFile 读取并校验一个本地目录文件。
+func readCatalogFile(path string) ([]Livery, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开 %s: %w", path, err)
+	}
+	liveries, err := parseCatalog(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return liveries, nil
+}
+
+// enabledSources 按优先级从高到低返回启用的来源，优先级相同的保持配置中的顺序。http 来源不会被使用。
+func enabledSources(cfg *appConfig) []catalogSourceConfig {
+	var sources []catalogSourceConfig
+	for _, s := range cfg.catalogSources() {
+		if s.enabled() && !s.insecure() {
+			sources = append(sources, s)
+		}
+	}
+	slices.SortStableFunc(sources, func(a, b catalogSourceConfig) int { return b.Priority - a.Priority })
+	return sources
+}
+
+// loadCatalog 合并所有启用的来源：同一 id 的涂装只保留优先级最高的来源中的，并记下来源名称。
+// 读取失败的来源被跳过，错误合并后与其它来源的涂装一起返回。
+func loadCatalog(cfg *appConfig) ([]Livery, error) {
+	liveries := []Livery{}
+	seen := make(map[string]bool)
+	var errs []error
+	for _, s := range enabledSources(cfg) {
+		ls, err := s.load()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.Name, err))
+		}
+		for _, l := range ls {
+			if seen[l.ID] {
+				continue
+			}
+			seen[l.ID] = true
+			l.Source = s.Name
+			liveries = append(liveries, l)
+		}
+	}
+	return liveries, errors.Join(errs...)
+}
+
+// refreshSource 刷新一个远程来源：带上次的 ETag/Last-Modified 发出条件请求，服务器上的目录没有变化时不下载；
+// 下载的新目录先完整校验，通过后才原子地替换缓存，原来的缓存保留为备份。
+// 返回目录是否有变化；刷新成功但无法保存刷新记录时 stateErr 不为 nil，目录照常使用。
+func refreshSource(ctx context.Context, state *AppState, s catalogSourceConfig, sink engine.Sink) (changed bool, stateErr error, err error) {
+	cache := s.cache()
+	prev := cache.loadState()
+	var validators engine.RemotePackageInfo
+	if current, err := cache.load(); err == nil && len(current) > 0 {
+		// 本地没有可用的目录（包括只能改用备份）时无条件下载
+		validators = engine.RemotePackageInfo{ETag: prev.ETag, LastModified: prev.LastModified}
+	}
+	data, remote, err := engine.FetchIfChanged(ctx, s.Location, validators, sink)
+	now := time.Now()
+	if errors.Is(err, engine.ErrNotModified) {
+		prev.RefreshedAt = now
+		if err := cache.saveState(prev); err != nil {
+			return false, fmt.Errorf("%s: %w", state.tr("catalog_state_save_error"), err), nil
+		}
+		return false, nil, nil
+	}
+	if isCancelled(err) {
+		return false, nil, err
+	}
+	if err != nil {
+		return false, nil, withAttemptHistory(state, state.tr("livery_list_download_error"), err)
+	}
+	liveries, err := parseCatalog(data)
+	if err != nil {
+		return false, nil, fmt.Errorf("%s: %w", state.tr("livery_list_invalid_error"), err)
+	}
+	if len(liveries) == 0 {
+		return false, nil, errors.New(state.tr("livery_list_empty_error"))
+	}
+	if err := cache.replace(data); err != nil {
+		return false, nil, fmt.Errorf("%s: %w", state.tr("livery_list_save_error"), err)
+	}
+	if err := cache.saveState(catalogState{ETag: remote.ETag, LastModified: remote.LastModified, RefreshedAt: now}); err != nil {
+		return true, fmt.Errorf("%s: %w", state.tr("catalog_state_save_error"), err), nil
+	}
+	return true, nil, nil
+}
+
+// catalogRefreshedText 返回"上次刷新"的说明：启用的远程来源中最早的一次刷新时间，有来源从未刷新过时说明尚未刷新。
+// 只有本地来源时返回空字符串。
+func catalogRefreshedText(state *AppState) string {
+	var oldest time.Time
+	for _, s := range enabledSources(state.config) {
+		if !s.remote() {
+			continue
+		}
+		refreshed := s.cache().loadState().RefreshedAt
+		if refreshed.IsZero() {
+			return state.tr("catalog_never_refreshed_label")
+		}
+		if oldest.IsZero() || refreshed.Before(oldest) {
+			oldest = refreshed
+		}
+	}
+	if oldest.IsZero() {
+		return ""
+	}
+	return state.tr("catalog_last_refreshed_label", oldest.Local().Format("2006-01-02 15:04"))
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// createCatalogSourcesSection 是设置页面中的涂装目录来源：启用或停用、编辑、删除和添加来源。
+func createCatalogSourcesSection(state *AppState) fyne.CanvasObject {
+	rows := container.NewVBox()
+	for i, s := range state.config.catalogSources() {
+		enabledCheck := widget.NewCheck(s.Name, nil)
+		enabledCheck.SetChecked(s.enabled())
+		enabledCheck.OnChanged = func(on bool) {
+			sources := slices.Clone(state.config.catalogSources())
+			sources[i].Enabled = &on
+			saveCatalogSources(state, sources)
+		}
+		info := widget.NewLabel(state.tr("catalog_source_info", s.Priority, s.Location))
+		info.Truncation = fyne.TextTruncateEllipsis
+		editBtn := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() { handleEditCatalogSource(state, i) })
+		deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			sources := state.config.catalogSources()
+			if len(sources) <= 1 {
+				dialog.ShowError(fmt.Errorf("%s", state.tr("catalog_source_last_error")), state.mainWindow)
+				return
+			}
+			dialog.ShowConfirm(state.tr("catalog_source_delete_title"), state.tr("catalog_source_delete_message", s.Name), func(confirm bool) {
+				if confirm {
+					saveCatalogSources(state, slices.Delete(slices.Clone(sources), i, i+1))
+				}
+			}, state.mainWindow)
+		})
+		rows.Add(container.NewBorder(nil, nil, enabledCheck, container.NewHBox(editBtn, deleteBtn), info))
+	}
+	addBtn := widget.NewButton(state.tr("catalog_source_add_button"), func() { handleEditCatalogSource(state, -1) })
+	return container.NewVBox(
+		widget.NewLabelWithStyle(state.tr("catalog_sources_label"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel(state.tr("catalog_sources_hint")),
+		rows,
+		addBtn,
+	)
+}
+
+// handleEditCatalogSource 编辑第 index 个来源，index 为 -1 时添加新来源。
+func handleEditCatalogSource(state *AppState, index int) {
+	sources := slices.Clone(state.config.catalogSources())
+	var current catalogSourceConfig
+	title := state.tr("catalog_source_add_button")
+	if index >= 0 {
+		current = sources[index]
+		title = state.tr("catalog_source_edit_title")
+	}
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(current.Name)
+	locationEntry := widget.NewEntry()
+	locationEntry.SetText(current.Location)
+	locationEntry.SetPlaceHolder(state.tr("catalog_source_location_placeholder"))
+	priorityEntry := widget.NewEntry()
+	priorityEntry.SetText(strconv.Itoa(current.Priority))
+	enabledCheck := widget.NewCheck(state.tr("catalog_source_enabled_check"), nil)
+	enabledCheck.SetChecked(index < 0 || current.enabled())
+	items := []*widget.FormItem{
+		widget.NewFormItem(state.tr("catalog_source_name_label"), nameEntry),
+		widget.NewFormItem(state.tr("catalog_source_location_label"), locationEntry),
+		widget.NewFormItem(state.tr("catalog_source_priority_label"), priorityEntry),
+		widget.NewFormItem("", enabledCheck),
+	}
+	form := dialog.NewForm(title, state.tr("confirm_button"), state.tr("cancel_button"), items, func(confirm bool) {
+		if !confirm {
+			return
+		}
+		enabled := enabledCheck.Checked
+		s := catalogSourceConfig{
+			Name:     strings.TrimSpace(nameEntry.Text),
+			Location: strings.TrimSpace(locationEntry.Text),
+			Enabled:  &enabled,
+		}
+		priority, err := strconv.Atoi(strings.TrimSpace(priorityEntry.Text))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s", state.tr("catalog_source_priority_error")), state.mainWindow)
+			return
+		}
+		s.Priority = priority
+		if msg := validateCatalogSource(state, s, sources, index); msg != "" {
+			dialog.ShowError(fmt.Errorf("%s", msg), state.mainWindow)
+			return
+		}
+		if index >= 0 {
+			sources[index] = s
+		} else {
+			sources = append(sources, s)
+		}
+		saveCatalogSources(state, sources)
+	}, state.mainWindow)
+	form.Resize(fyne.NewSize(500, 300))
+	form.Show()
+}
+
+// validateCatalogSource 检查来源的名称和位置，返回翻译后的错误说明，没有问题时返回空字符串。
+// index 是正在编辑的来源在 sources 中的位置，名称可以与它自己相同。
+func validateCatalogSource(state *AppState, s catalogSourceConfig, sources []catalogSourceConfig, index int) string {
+	if s.Name == "" {
+		return state.tr("catalog_source_name_error")
+	}
+	for i, other := range sources {
+		if i != index && strings.EqualFold(other.Name, s.Name) {
+			return state.tr("catalog_source_duplicate_error", s.Name)
+		}
+	}
+	switch {
+	case s.insecure():
+		return state.tr("catalog_source_https_error")
+	case s.remote():
+	default:
+		if _, err := os.Stat(s.Location); err != nil {
+			return state.tr("catalog_source_location_error", s.Location)
+		}
+	}
+	return ""
+}
+
+// saveCatalogSources 保存来源并重新合并目录，界面随之重建。远程来源使用上次刷新的缓存，新来源要刷新涂装列表后才有内容。
+func saveCatalogSources(state *AppState, sources []catalogSourceConfig) {
+	state.config.CatalogSources = sources
+	if err := writeConfig(state); err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %w", state.tr("save_config_error"), err), state.mainWindow)
+		return
+	}
+	liveries, err := loadCatalog(state.config)
+	state.liveries = liveries
+	state.mainWindow.SetContent(createMainUI(state))
+	if err != nil {
+		dialog.ShowError(err, state.mainWindow)
+	}
+}
@@ -0,0 +1,686 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"myapp/engine"
+)
+
+// 命令行模式的退出码。
+const (
+	exitOK             = 0
+	exitFailed         = 1 // 操作失败
+	exitUsage          = 2 // 命令或参数错误
+	exitAborted        = 3 // 需要确认但没有确认（非交互运行时请加 --yes）
+	exitVerifyProblems = 4 // verify 发现缺失或被修改的文件
+	exitPartial        = 5 // 部分涂装或配置档安装失败
+	exitCancelled      = 6 // 被 Ctrl+C 取消，未完成的下载和解压文件已删除
+)
+
+const cliUsage = `Usage: %[1]s <command> [flags] [arguments]
+
+Commands:
+  install-aircraft              download and install (or update) the aircraft
+  uninstall-aircraft            delete the aircraft folder
+  verify                        check installed aircraft files against the install receipt
+  liveries list [search...]     list liveries in the catalog, optionally only those matching
+                                every search word (name, airline, registration or ICAO code)
+  liveries install <id|name>... install liveries (--all for every livery in the catalog,
+                                --outdated to update installed liveries that changed in the catalog)
+  liveries uninstall <name>...  uninstall livery folders or catalog ids
+  update-list                   refresh the livery catalog if it changed and print what changed
+  self-update                   replace this program with the latest version
+  version                       print the program version
+
+Flags (before the arguments):
+  --json            print one JSON object on stdout instead of text, and progress
+                    events as JSON lines on stderr
+  --yes             answer yes to confirmations
+  --profile NAME    use this profile instead of the active one
+  --target NAME     install to this profile as well (repeatable; install-aircraft, liveries install)
+  --all             liveries install: install every livery in the catalog
+  --outdated        liveries install: reinstall installed liveries whose catalog version or hash changed
+  --force           self-update: reinstall even if no newer version is published
+
+Exit codes: 0 ok, 1 failed, 2 usage error, 3 not confirmed, 4 verify found problems, 5 partially failed,
+6 cancelled (Ctrl+C).
+`
+
+// cliCommands 是命令行模式识别的命令，第一个参数不是这些命令时启动图形界面。
+var cliCommands = map[string]bool{
+	"install-aircraft":   true,
+	"uninstall-aircraft": true,
+	"verify":             true,
+	"liveries":           true,
+	"update-list":        true,
+	"self-update":        true,
+	"version":            true,
+	"help":               true,
+}
+
+// cliResult 是 --json 模式下输出的对象。
+type cliResult struct {
+	Command string `json:"command"`
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+	Data    any    `json:"data,omitempty"`
+}
+
+type cliOptions struct {
+	json     bool
+	yes      bool
+	all      bool
+	outdated bool
+	force    bool
+	profile  string
+	targets  []string
+}
+
+// cli 保存一次命令行调用的上下文。
+type cli struct {
+	state   *AppState
+	opts    cliOptions
+	command string
+	out     io.Writer       // 结果输出，进度、警告和错误写到 stderr
+	ctx     context.Context // 按 Ctrl+C 时取消正在进行的下载和安装
+
+	originalProfile string // 使用 --profile 时原来的当前配置档
+}
+
+// cliReporter 在文本模式下把状态写到 stderr，每秒最多一行；--json 模式下改为把引擎的进度事件逐行以 JSON 写到 stderr。
+type cliReporter struct {
+	json     bool
+	last     string
+	lastTime time.Time
+}
+
+func (r *cliReporter) Status(msg string) {
+	if r.json || msg == r.last || time.Since(r.lastTime) < time.Second {
+		return
+	}
+	r.last, r.lastTime = msg, time.Now()
+	fmt.Fprintln(os.Stderr, msg)
+}
+
+func (r *cliReporter) Progress(fraction float64) {}
+
+func (r *cliReporter) Event(e engine.Event) {
+	if r.json {
+		json.NewEncoder(os.Stderr).Encode(e)
+	}
+}
+
+// warn 立即输出警告，不受每秒一行的限制。
+func (r *cliReporter) warn(msg string) {
+	r.last, r.lastTime = msg, time.Now()
+	if !r.json {
+		fmt.Fprintln(os.Stderr, msg)
+	}
+}
+
+// runCLI 在第一个参数是命令时以命令行模式运行并返回退出码；ok 为 false 时应启动图形界面。
+func runCLI(args []string) (code int, ok bool) {
+	var afterUpdate string
+	if len(args) >= 2 && args[0] == engine.AfterUpdateFlag {
+		afterUpdate, args = args[1], args[2:]
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return 0, false
+	}
+	if afterUpdate != "" {
+		engine.FinishSelfUpdate(afterUpdate, AppVersion)
+	} else {
+		engine.RemoveOldExecutable()
+	}
+	c := &cli{command: args[0], out: os.Stdout}
+	if !cliCommands[c.command] {
+		fmt.Fprintf(os.Stderr, cliUsage, exeName())
+		return exitUsage, true
+	}
+	if c.command == "help" {
+		fmt.Fprintf(c.out, cliUsage, exeName())
+		return exitOK, true
+	}
+	args = args[1:]
+	if c.command == "liveries" {
+		if len(args) == 0 {
+			fmt.Fprintf(os.Stderr, cliUsage, exeName())
+			return exitUsage, true
+		}
+		c.command, args = "liveries "+args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet(c.command, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&c.opts.json, "json", false, "")
+	fs.BoolVar(&c.opts.yes, "yes", false, "")
+	fs.BoolVar(&c.opts.all, "all", false, "")
+	fs.BoolVar(&c.opts.outdated, "outdated", false, "")
+	fs.BoolVar(&c.opts.force, "force", false, "")
+	fs.StringVar(&c.opts.profile, "profile", "", "")
+	fs.Func("target", "", func(name string) error {
+		c.opts.targets = append(c.opts.targets, name)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return c.usageError(err), true
+	}
+	if err := c.setup(); err != nil {
+		return c.usageError(err), true
+	}
+	return c.run(fs.Args()), true
+}
+
+func exeName() string {
+	if exe, err := os.Executable(); err == nil {
+		return strings.TrimSuffix(filepath.Base(exe), ".exe")
+	}
+	return "AeroGennis_Updater"
+}
+
+// setup 读取配置并载入 --profile 指定的配置档。
+func (c *cli) setup() error {
+	engine.RecoverStagedInstall(func(e engine.Event) {
+		fmt.Fprintln(os.Stderr, e.Err)
+	})
+	cfg, configWarnings, configErr := readConfig()
+	c.state = &AppState{config: cfg}
+	if c.opts.profile != "" {
+		if c.state.config.profile(c.opts.profile) == nil {
+			return fmt.Errorf("profile %q does not exist", c.opts.profile)
+		}
+		c.originalProfile = c.state.config.ActiveProfile
+		c.state.config.ActiveProfile = c.opts.profile
+	}
+	for _, name := range c.opts.targets {
+		if c.state.config.profile(name) == nil {
+			return fmt.Errorf("profile %q does not exist", name)
+		}
+	}
+	p := c.state.config.activeProfile()
+	c.state.xpPath, c.state.language, c.state.ag330Path = p.XPlanePath, p.Language, p.AG330Path
+	loadTranslations(c.state)
+	if configErr != nil {
+		fmt.Fprintln(os.Stderr, configErrorText(c.state, configErr))
+	}
+	for _, w := range configWarnings {
+		fmt.Fprintln(os.Stderr, c.state.tr(w.key, w.args...))
+	}
+	if c.state.xpPath != "" {
+		if valid, _ := engine.ValidateXPlaneDir(c.state.xpPath); valid {
+			checkAircraftInstallation(c.state)
+		} else {
+			c.state.xpPath = ""
+		}
+	}
+	liveries, err := loadCatalog(c.state.config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	c.state.liveries = liveries
+	return nil
+}
+
+// restoreActiveProfile 让 --profile 只对本次调用生效：操作中保存配置时会写入临时切换的配置档，这里再改回来。
+func (c *cli) restoreActiveProfile() {
+	if c.originalProfile == "" {
+		return
+	}
+	cfg, _, err := loadConfig()
+	if err == nil && cfg.ActiveProfile != c.originalProfile && cfg.profile(c.originalProfile) != nil {
+		cfg.ActiveProfile = c.originalProfile
+		cfg.save()
+	}
+}
+
+func (c *cli) run(args []string) int {
+	defer c.restoreActiveProfile()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	c.ctx = ctx
+	rep := &cliReporter{json: c.opts.json}
+	switch c.command {
+	case "version":
+		return c.finish(map[string]string{"version": AppVersion}, AppVersion, nil)
+	case "install-aircraft":
+		return c.installAircraft(rep)
+	case "uninstall-aircraft":
+		return c.uninstallAircraft()
+	case "verify":
+		return c.verify(rep)
+	case "liveries list":
+		return c.listLiveries(args)
+	case "liveries install":
+		return c.installLiveries(args, rep)
+	case "liveries uninstall":
+		return c.uninstallLiveries(args)
+	case "update-list":
+		refresh, err := updateLiveryList(c.ctx, c.state, rep)
+		if err != nil {
+			return c.fail(exitFailed, err)
+		}
+		return c.finish(newCLIRefresh(refresh), catalogRefreshText(c.state, refresh), nil)
+	case "self-update":
+		return c.selfUpdate(rep)
+	}
+	return c.usageError(fmt.Errorf("unknown command %q", c.command))
+}
+
+// finish 输出成功的结果：--json 时输出 data，否则输出 text。
+func (c *cli) finish(data any, text string, err error) int {
+	if c.opts.json {
+		c.writeJSON(cliResult{Command: c.command, OK: err == nil, Data: data, Error: errorText(err)})
+	} else {
+		if text != "" {
+			fmt.Fprintln(c.out, text)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+	return exitOK
+}
+
+// fail 输出错误并返回 code；操作被取消时改为返回 exitCancelled。
+func (c *cli) fail(code int, err error) int {
+	if isCancelled(err) {
+		code, err = exitCancelled, errors.New(c.state.tr("operation_cancelled_status"))
+	}
+	if c.opts.json {
+		c.writeJSON(cliResult{Command: c.command, Error: errorText(err)})
+	} else {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return code
+}
+
+func (c *cli) usageError(err error) int {
+	if !c.opts.json {
+		fmt.Fprintf(os.Stderr, "%v\n\n", err)
+		fmt.Fprintf(os.Stderr, cliUsage, exeName())
+		return exitUsage
+	}
+	return c.fail(exitUsage, err)
+}
+
+func (c *cli) writeJSON(v any) {
+	enc := json.NewEncoder(c.out)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// confirm 代替图形界面的确认对话框：有 --yes 时直接同意，在终端中询问，其它情况视为不同意。
+func (c *cli) confirm(prompt string) bool {
+	if c.opts.yes {
+		return true
+	}
+	if c.opts.json {
+		return false
+	}
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
+
+func (c *cli) notConfirmed() int {
+	return c.fail(exitAborted, errors.New("not confirmed; pass --yes to run without a prompt"))
+}
+
+// targetProfiles 返回 --target 指定的配置档，没有指定时只用当前配置档。
+func (c *cli) targetProfiles() []string {
+	targets := []string{c.state.config.ActiveProfile}
+	for _, name := range c.opts.targets {
+		if name != targets[0] {
+			targets = append(targets, name)
+		}
+	}
+	return targets
+}
+
+func (c *cli) requireXPlane() error {
+	if c.state.xpPath == "" {
+		return errors.New(c.state.tr("invalid_xp_path_error"))
+	}
+	return nil
+}
+
+func (c *cli) requireAircraft() error {
+	if !c.state.isAircraftInstalled {
+		return errors.New(c.state.tr("find_aircraft_dir_error"))
+	}
+	return nil
+}
+
+func (c *cli) installAircraft(rep reporter) int {
+	if err := c.requireXPlane(); err != nil {
+		return c.fail(exitFailed, err)
+	}
+	results, err := installAircraftToProfiles(c.ctx, c.state, c.targetProfiles(), rep)
+	if err != nil {
+		return c.fail(exitFailed, localizeError(c.state, err))
+	}
+	failures := installFailures(results)
+	switch {
+	case len(failures) == len(results):
+		if c.opts.json {
+			c.writeJSON(cliResult{Command: c.command, Data: results, Error: errors.Join(failures...).Error()})
+		} else {
+			fmt.Fprintln(os.Stderr, errors.Join(failures...))
+		}
+		return exitFailed
+	case len(failures) > 0:
+		c.finish(results, "", errors.Join(failures...))
+		return exitPartial
+	}
+	var lines []string
+	for _, r := range results {
+		lines = append(lines, fmt.Sprintf("%s: %s %s", r.Profile, r.Path, r.Version))
+	}
+	if len(results) > 0 {
+		if warning := unverifiedWarning(c.state, results[0]); warning != "" {
+			lines = append(lines, warning)
+		}
+	}
+	return c.finish(results, c.state.tr("install_complete_status")+"\n"+strings.Join(lines, "\n"), nil)
+}
+
+func (c *cli) uninstallAircraft() int {
+	if err := c.requireAircraft(); err != nil {
+		return c.fail(exitFailed, err)
+	}
+	path := c.state.ag330Path
+	if !c.confirm(c.state.tr("uninstall_aircraft_confirm_message", path)) {
+		return c.notConfirmed()
+	}
+	if err := uninstallAircraft(c.state); err != nil {
+		return c.fail(exitFailed, fmt.Errorf("%s: %w", c.state.tr("uninstall_aircraft_error"), err))
+	}
+	return c.finish(map[string]string{"path": path}, c.state.tr("uninstall_aircraft_success_message"), nil)
+}
+
+func (c *cli) verify(rep reporter) int {
+	if err := c.requireAircraft(); err != nil {
+		return c.fail(exitFailed, err)
+	}
+	receipt, problems, err := verifyAircraftInstall(c.ctx, c.state, rep)
+	if err != nil {
+		return c.fail(exitFailed, err)
+	}
+	if receipt == nil {
+		return c.fail(exitFailed, errors.New(c.state.tr("verify_no_receipt_message")))
+	}
+	data := map[string]any{"path": receipt.Root, "files": len(receipt.Files), "problems": problems}
+	if len(problems) == 0 {
+		return c.finish(data, c.state.tr("verify_ok_message", len(receipt.Files)), nil)
+	}
+	if c.opts.json {
+		c.writeJSON(cliResult{Command: c.command, Data: data, Error: fmt.Sprintf("%d problems", len(problems))})
+	} else {
+		for _, p := range problems {
+			if p.Missing {
+				fmt.Fprintln(c.out, c.state.tr("verify_missing_file", p.Path))
+			} else {
+				fmt.Fprintln(c.out, c.state.tr("verify_changed_file", p.Path))
+			}
+		}
+	}
+	return exitVerifyProblems
+}
+
+// cliRefresh 是 update-list 的结果，变化的涂装以 id 列出。
+type cliRefresh struct {
+	Liveries    int       `json:"liveries"`
+	NotModified bool      `json:"not_modified"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+	Added       []string  `json:"added"`
+	Removed     []string  `json:"removed"`
+	Changed     []string  `json:"changed"`
+	Failed      []string  `json:"failed,omitempty"` // 刷新失败的来源，继续使用上次的目录
+}
+
+func newCLIRefresh(r *catalogRefresh) cliRefresh {
+	ids := func(liveries []Livery) []string {
+		out := make([]string, 0, len(liveries))
+		for _, l := range liveries {
+			out = append(out, l.ID)
+		}
+		return out
+	}
+	var failed []string
+	for _, err := range r.Failures {
+		failed = append(failed, err.Error())
+	}
+	return cliRefresh{Liveries: len(r.Liveries), NotModified: r.NotModified, RefreshedAt: r.RefreshedAt,
+		Added: ids(r.Diff.Added), Removed: ids(r.Diff.Removed), Changed: ids(r.Diff.Changed), Failed: failed}
+}
+
+// cliLivery 是 liveries list 输出的一项。
+type cliLivery struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Airline      string `json:"airline,omitempty"`
+	Registration string `json:"registration,omitempty"`
+	ICAO         string `json:"icao,omitempty"`
+	Version      string `json:"version,omitempty"`
+	Author       string `json:"author,omitempty"`
+	Source       string `json:"source,omitempty"`
+	Installed    bool   `json:"installed"`
+	Outdated     bool   `json:"outdated,omitempty"`
+}
+
+// liveryInstalls 返回目录涂装的安装情况，对照涂装文件夹失败时把错误写到 stderr，所有涂装按未安装处理。
+func (c *cli) liveryInstalls() map[string]engine.LiveryInstall {
+	installs, err := liveryInstalls(c.state)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, c.state.tr("livery_reconcile_error", err))
+	}
+	return installs
+}
+
+// listLiveries 列出目录中的涂装，args 是可选的搜索词，规则与涂装页的搜索框相同。
+// 文本输出中已安装的涂装标 *，已安装但过期的标 +。
+func (c *cli) listLiveries(args []string) int {
+	installs := c.liveryInstalls()
+	query := strings.Join(args, " ")
+	list := make([]cliLivery, 0, len(c.state.liveries))
+	var lines []string
+	for _, l := range c.state.liveries {
+		if !matchesQuery(l, query) {
+			continue
+		}
+		state := installs[l.ID].State
+		item := cliLivery{ID: l.ID, Name: l.Name, Airline: l.Airline, Registration: l.Registration, ICAO: l.ICAO, Version: l.Version, Author: l.Author, Source: l.Source,
+			Installed: state != engine.LiveryNotInstalled, Outdated: state == engine.LiveryOutdated}
+		list = append(list, item)
+		mark := " "
+		switch state {
+		case engine.LiveryInstalled:
+			mark = "*"
+		case engine.LiveryOutdated:
+			mark = "+"
+		}
+		lines = append(lines, fmt.Sprintf("%s %-24s %s", mark, l.ID, l.Name))
+	}
+	return c.finish(list, strings.Join(lines, "\n"), nil)
+}
+
+// findLivery 按 id 或名称（不区分大小写）查找目录中的涂装。
+func (c *cli) findLivery(arg string) (Livery, bool) {
+	for _, l := range c.state.liveries {
+		if l.ID == arg || strings.EqualFold(l.Name, arg) {
+			return l, true
+		}
+	}
+	return Livery{}, false
+}
+
+// cliLiveryResult 是 liveries install 中一个涂装的结果。
+type cliLiveryResult struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+func (c *cli) installLiveries(args []string, rep reporter) int {
+	if err := c.requireAircraft(); err != nil {
+		return c.fail(exitFailed, err)
+	}
+	var queue []Livery
+	switch {
+	case c.opts.all:
+		queue = c.state.liveries
+	case c.opts.outdated:
+		installs := c.liveryInstalls()
+		for _, l := range c.state.liveries {
+			if installs[l.ID].State == engine.LiveryOutdated {
+				queue = append(queue, l)
+			}
+		}
+		if len(queue) == 0 {
+			return c.finish([]cliLiveryResult{}, c.state.tr("no_outdated_liveries_message"), nil)
+		}
+	default:
+		seen := make(map[string]bool)
+		for _, arg := range args {
+			l, ok := c.findLivery(arg)
+			if !ok {
+				return c.usageError(fmt.Errorf("livery %q is not in the catalog", arg))
+			}
+			if !seen[l.ID] {
+				seen[l.ID] = true
+				queue = append(queue, l)
+			}
+		}
+	}
+	if len(queue) == 0 {
+		return c.usageError(errors.New(c.state.tr("no_livery_selected_message")))
+	}
+	liveryDirs := profileLiveryDirs(c.state, c.targetProfiles())
+	var results []cliLiveryResult
+	var failures []error
+	for i, l := range queue {
+		rep.Status(c.state.tr("batch_download_progress_label", i+1, len(queue), l.Name))
+		result := cliLiveryResult{ID: l.ID, Name: l.Name}
+		if l.requiresNewerAircraft(c.state.installedAircraftVersion) {
+			err := errors.New(c.state.tr("livery_requires_newer_aircraft_error", l.MinAircraftVersion, c.state.installedAircraftVersion))
+			result.Error = err.Error()
+			failures = append(failures, fmt.Errorf("%s: %w", l.Name, err))
+			results = append(results, result)
+			continue
+		}
+		err := engine.InstallLivery(c.ctx, l.pkg(), liveryDirs, eventSink(c.state, rep))
+		if isCancelled(err) {
+			return c.fail(exitCancelled, err)
+		}
+		if err != nil {
+			err = localizeError(c.state, err)
+			result.Error = err.Error()
+			failures = append(failures, fmt.Errorf("%s: %w", l.Name, err))
+		}
+		results = append(results, result)
+	}
+	if len(failures) == 0 {
+		return c.finish(results, c.state.tr("batch_install_complete_message", len(queue)), nil)
+	}
+	if c.opts.json {
+		c.writeJSON(cliResult{Command: c.command, Data: results, Error: errors.Join(failures...).Error()})
+	} else {
+		fmt.Fprintln(os.Stderr, errors.Join(failures...))
+	}
+	if len(failures) == len(queue) {
+		return exitFailed
+	}
+	return exitPartial
+}
+
+func (c *cli) uninstallLiveries(args []string) int {
+	if err := c.requireAircraft(); err != nil {
+		return c.fail(exitFailed, err)
+	}
+	if len(args) == 0 {
+		return c.usageError(errors.New("no livery given"))
+	}
+	liveriesPath := filepath.Join(c.state.ag330Path, "liveries")
+	installedDirs, err := engine.InstalledLiveryDirs(liveriesPath)
+	if err != nil {
+		return c.fail(exitFailed, fmt.Errorf("%s: %w", c.state.tr("scan_liveries_dir_error"), err))
+	}
+	isInstalled := make(map[string]bool)
+	for _, name := range installedDirs {
+		isInstalled[name] = true
+	}
+	// 参数可以是涂装文件夹名，也可以是目录中的 id（按安装收据或同名文件夹找到文件夹）
+	installs := c.liveryInstalls()
+	var names []string
+	for _, arg := range args {
+		if isInstalled[arg] {
+			names = append(names, arg)
+			continue
+		}
+		dirs := installs[arg].Dirs
+		names = append(names, dirs...)
+		if len(dirs) == 0 {
+			return c.usageError(fmt.Errorf("livery %q is not installed", arg))
+		}
+	}
+	if !c.confirm(c.state.tr("uninstall_final_confirm_message", len(names), strings.Join(names, "\n- "))) {
+		return c.notConfirmed()
+	}
+	deletedCount, errorMessages := engine.UninstallLiveryDirs(liveriesPath, names)
+	data := map[string]any{"deleted": deletedCount, "errors": errorMessages}
+	if len(errorMessages) == 0 {
+		return c.finish(data, c.state.tr("uninstall_report_message", deletedCount), nil)
+	}
+	err = errors.New(c.state.tr("uninstall_report_errors", len(errorMessages), strings.Join(errorMessages, "\n")))
+	if c.opts.json {
+		c.writeJSON(cliResult{Command: c.command, Data: data, Error: err.Error()})
+	} else {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if deletedCount == 0 {
+		return exitFailed
+	}
+	return exitPartial
+}
+
+func (c *cli) selfUpdate(rep reporter) int {
+	checkAppUpdate(c.state)
+	if c.state.appUpdate == nil && !c.opts.force {
+		return c.finish(map[string]string{"version": AppVersion}, c.state.tr("current_app_version_label", AppVersion), nil)
+	}
+	version := AppVersion
+	if c.state.appUpdate != nil {
+		version = c.state.appUpdate.Version
+	}
+	msg, unverified := updateConfirmMessage(c.state)
+	if !c.confirm(msg) {
+		return c.notConfirmed()
+	}
+	// 新版本以 version 命令启动，报告启动成功后立即退出
+	if err := selfUpdate(c.ctx, c.state, rep, unverified, "version"); err != nil {
+		return c.fail(exitFailed, err)
+	}
+	return c.finish(map[string]string{"version": version}, version, nil)
+}
@@ -0,0 +1,497 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"myapp/engine"
+)
+
+const (
+	configFileName       = "Ag330UpdaterConf.toml"
+	legacyConfigFileName = "Ag330UpdaterConf.txt"
+	configSchemaVersion  = 2
+	defaultProfileName   = "Default"
+)
+
+// appConfig 是保存在 Ag330UpdaterConf.toml 中的设置：
+//
+//	schema_version = 2
+//	active_profile = "Stable"
+//	update_channel = ""   # 为空时跟随本程序版本号的渠道
+//
+//	[download]
+//	max_attempts = 5
+//	base_delay_seconds = 2
+//	max_delay_seconds = 60
+//	concurrency = 4              # 同时安装的涂装数
+//	adaptive_concurrency = false # 按吞吐量和错误率在 1 到 max_concurrency 之间自动调整
+//	max_concurrency = 8
+//	bandwidth_limit_kbps = 0     # 所有下载合计的速度上限（KB/s），0 为不限
+//
+//	[[download.bandwidth_schedule]] # 按时段的速度上限，优先于 bandwidth_limit_kbps，靠前的规则优先
+//	start = "22:00"
+//	end = "07:00"                 # 不晚于 start 时跨过午夜
+//	limit_kbps = 0
+//
+//	[[catalog_sources]]          # 涂装目录的来源，省略时只使用官方目录
+//	name = "Official"
+//	location = "https://..."      # https 链接，或本地的目录文件、存放目录文件的文件夹
+//	priority = 0                  # 多个来源有同一 id 的涂装时使用优先级高的
+//	enabled = true
+//
+//	[[profiles]]
+//	name = "Stable"
+//	xplane_path = 'D:\X-Plane 12'
+//	ag330_path = ""
+//	language = "zh-CN"
+//
+// 文件中本程序不认识的键会原样保留，旧版本程序打开新版本的配置文件不会丢失设置。
+type appConfig struct {
+	SchemaVersion int             `toml:"schema_version"`
+	ActiveProfile string          `toml:"active_profile"`
+	UpdateChannel string          `toml:"update_channel"`
+	Download      downloadConfig  `toml:"download"`
+	Profiles      []profileConfig `toml:"profiles"`
+
+	CatalogSources []catalogSourceConfig `toml:"catalog_sources,omitempty"`
+
+	raw     map[string]any // 读取时的全部键值，保存时用来保留未知的键
+	saveErr error          // 配置文件无法解析又没能备份时不再保存，以免覆盖用户的文件
+}
+
+// profileConfig 是一个 X-Plane 安装的设置，同一台电脑上可以有多个（例如正式版和测试版）。
+type profileConfig struct {
+	Name       string `toml:"name"`
+	XPlanePath string `toml:"xplane_path"`
+	AG330Path  string `toml:"ag330_path"`
+	Language   string `toml:"language"`
+}
+
+// legacyConfigV1 是 schema_version 1 中放在顶层的单个安装的设置。
+type legacyConfigV1 struct {
+	XPlanePath string `toml:"xplane_path"`
+	Language   string `toml:"language"`
+	AG330Path  string `toml:"ag330_path"`
+}
+
+type downloadConfig struct {
+	MaxAttempts         int  `toml:"max_attempts"`
+	BaseDelaySeconds    int  `toml:"base_delay_seconds"`
+	MaxDelaySeconds     int  `toml:"max_delay_seconds"`
+	Concurrency         int  `toml:"concurrency"`
+	AdaptiveConcurrency bool `toml:"adaptive_concurrency"`
+	MaxConcurrency      int  `toml:"max_concurrency"`
+	BandwidthLimitKBps  int  `toml:"bandwidth_limit_kbps"`
+
+	BandwidthSchedule []bandwidthRuleConfig `toml:"bandwidth_schedule,omitempty"`
+}
+
+// bandwidthRuleConfig 是一条按时段的速度上限，时间为 24 小时制的 "HH:MM"。
+type bandwidthRuleConfig struct {
+	Start     string `toml:"start"`
+	End       string `toml:"end"`
+	LimitKBps int    `toml:"limit_kbps"`
+}
+
+// parseTimeOfDay 把 "HH:MM" 解析为从零点起的时间。
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("无效的时间 %q，应为 HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// rule 返回引擎使用的时段规则。
+func (r bandwidthRuleConfig) rule() (engine.BandwidthRule, error) {
+	start, err := parseTimeOfDay(r.Start)
+	if err != nil {
+		return engine.BandwidthRule{}, err
+	}
+	end, err := parseTimeOfDay(r.End)
+	if err != nil {
+		return engine.BandwidthRule{}, err
+	}
+	return engine.BandwidthRule{Start: start, End: end, BytesPerSecond: int64(max(r.LimitKBps, 0)) * 1024}, nil
+}
+
+func defaultConfig() *appConfig {
+	return &appConfig{
+		SchemaVersion: configSchemaVersion,
+		Download: downloadConfig{
+			MaxAttempts:      5,
+			BaseDelaySeconds: 2,
+			MaxDelaySeconds:  60,
+			Concurrency:      4,
+			MaxConcurrency:   8,
+		},
+	}
+}
+
+// retryPolicy 返回配置的下载重试策略，非法的值使用默认值。
+func (c *appConfig) retryPolicy() engine.RetryPolicy {
+	def := defaultConfig().Download
+	d := c.Download
+	if d.MaxAttempts < 1 {
+		d.MaxAttempts = def.MaxAttempts
+	}
+	if d.BaseDelaySeconds < 0 {
+		d.BaseDelaySeconds = def.BaseDelaySeconds
+	}
+	if d.MaxDelaySeconds < d.BaseDelaySeconds {
+		d.MaxDelaySeconds = max(def.MaxDelaySeconds, d.BaseDelaySeconds)
+	}
+	return engine.RetryPolicy{
+		MaxAttempts: d.MaxAttempts,
+		BaseDelay:   time.Duration(d.BaseDelaySeconds) * time.Second,
+		MaxDelay:    time.Duration(d.MaxDelaySeconds) * time.Second,
+	}
+}
+
+// concurrency 返回涂装队列的并发设置，非法的值使用默认值。
+func (c *appConfig) concurrency() engine.Concurrency {
+	def := defaultConfig().Download
+	d := c.Download
+	if d.Concurrency < 1 {
+		d.Concurrency = def.Concurrency
+	}
+	if d.MaxConcurrency < d.Concurrency {
+		d.MaxConcurrency = max(def.MaxConcurrency, d.Concurrency)
+	}
+	return engine.Concurrency{Workers: d.Concurrency, Adaptive: d.AdaptiveConcurrency, MaxWorkers: d.MaxConcurrency}
+}
+
+// bandwidthLimit 返回所有下载合计的速度上限（字节/秒），0 为不限。
+func (c *appConfig) bandwidthLimit() int64 {
+	return int64(max(c.Download.BandwidthLimitKBps, 0)) * 1024
+}
+
+// bandwidthSchedule 返回按时段的速度上限规则，忽略时间格式错误的规则（读取配置时已经作为警告报告）。
+func (c *appConfig) bandwidthSchedule() []engine.BandwidthRule {
+	var rules []engine.BandwidthRule
+	for _, r := range c.Download.BandwidthSchedule {
+		if rule, err := r.rule(); err == nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// setBandwidthSchedule 替换时段规则。规则全部删除时也从读取时的原始键值中删除，否则保存时会把旧规则合并回来。
+func (c *appConfig) setBandwidthSchedule(rules []bandwidthRuleConfig) {
+	c.Download.BandwidthSchedule = rules
+	if len(rules) == 0 {
+		if download, ok := c.raw["download"].(map[string]any); ok {
+			delete(download, "bandwidth_schedule")
+		}
+	}
+}
+
+// catalogSources 返回涂装目录的来源，没有配置时返回只有官方目录的默认列表。
+func (c *appConfig) catalogSources() []catalogSourceConfig {
+	if len(c.CatalogSources) == 0 {
+		return []catalogSourceConfig{{Name: officialSourceName, Location: LiveryListURL}}
+	}
+	return c.CatalogSources
+}
+
+// configErrorText 返回配置文件无法读取时给用户看的说明：原文件已经备份时告诉用户备份的位置。
+func configErrorText(state *AppState, err error) string {
+	var corrupt *corruptConfigError
+	if errors.As(err, &corrupt) {
+		return state.tr("config_corrupt_message", corrupt.Err, corrupt.Backup)
+	}
+	return fmt.Sprintf("%s: %v", state.tr("config_load_error"), err)
+}
+
+// configWarning 是读取配置时被忽略或保留未用的设置，显示时用 key 和 args 翻译为当前语言。
+type configWarning struct {
+	key  string
+	args []any
+}
+
+// loadConfig 读取配置文件。只有旧的三行 txt 配置时自动迁移为 TOML；都不存在时返回默认配置。
+// 配置可以使用但有设置被忽略时返回 warnings，界面应该告诉用户。
+func loadConfig() (cfg *appConfig, warnings []configWarning, err error) {
+	path, err := engine.DataPath(configFileName)
+	if err != nil {
+		return defaultConfig(), nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		cfg, err := migrateLegacyConfig()
+		return cfg, nil, err
+	}
+	if err != nil {
+		return defaultConfig(), nil, err
+	}
+	cfg, err = parseConfig(data)
+	if err != nil {
+		return cfg, nil, backupCorruptConfig(path, cfg, err)
+	}
+	return cfg, cfg.warnings(), nil
+}
+
+// warnings 返回读取的配置中会被忽略的设置：比本程序新的 schema_version、时间格式错误的速度规则和 http 目录来源。
+func (c *appConfig) warnings() []configWarning {
+	var warnings []configWarning
+	if c.SchemaVersion > configSchemaVersion {
+		warnings = append(warnings, configWarning{"config_newer_schema_warning", []any{configFileName, c.SchemaVersion, configSchemaVersion}})
+	}
+	for _, r := range c.Download.BandwidthSchedule {
+		if _, err := r.rule(); err != nil {
+			warnings = append(warnings, configWarning{"config_rule_ignored_warning", []any{r.Start, r.End, err}})
+		}
+	}
+	for _, src := range c.CatalogSources {
+		if src.insecure() {
+			warnings = append(warnings, configWarning{"config_insecure_source_warning", []any{src.Name, src.Location}})
+		}
+	}
+	return warnings
+}
+
+// corruptConfigError 表示配置文件无法解析，原文件已改名为 Backup，程序使用默认配置。
+type corruptConfigError struct {
+	Backup string
+	Err    error
+}
+
+func (e *corruptConfigError) Error() string {
+	return fmt.Sprintf("%v（原文件已备份为 %s）", e.Err, e.Backup)
+}
+
+func (e *corruptConfigError) Unwrap() error {
+	return e.Err
+}
+
+// backupCorruptConfig 把无法解析的配置文件改名为 <文件名>.corrupt-<时间>，之后保存设置时不会覆盖用户原来的文件。
+// 改名失败时 cfg 不再保存。
+func backupCorruptConfig(path string, cfg *appConfig, parseErr error) error {
+	backup := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102-150405"))
+	if err := os.Rename(path, backup); err != nil {
+		cfg.saveErr = fmt.Errorf("%w；无法备份原文件，为避免覆盖不会保存设置: %v", parseErr, err)
+		return cfg.saveErr
+	}
+	return &corruptConfigError{Backup: backup, Err: parseErr}
+}
+
+func parseConfig(data []byte) (*appConfig, error) {
+	cfg := defaultConfig()
+	if _, err := toml.Decode(string(data), cfg); err != nil {
+		return defaultConfig(), fmt.Errorf("无法读取 %s: %w", configFileName, err)
+	}
+	if err := toml.Unmarshal(data, &cfg.raw); err != nil {
+		return defaultConfig(), fmt.Errorf("无法读取 %s: %w", configFileName, err)
+	}
+	if cfg.SchemaVersion < 2 {
+		var v1 legacyConfigV1
+		toml.Unmarshal(data, &v1)
+		cfg.migrateV1(v1)
+	}
+	return cfg, nil
+}
+
+// migrateLegacyConfig 把旧版本按行保存的 X-Plane 路径、语言和 AG330 路径转换为 TOML，成功后删除旧文件。
+func migrateLegacyConfig() (*appConfig, error) {
+	cfg := defaultConfig()
+	legacyPath, err := engine.DataPath(legacyConfigFileName)
+	if err != nil {
+		return cfg, err
+	}
+	data, err := os.ReadFile(legacyPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	var v1 legacyConfigV1
+	lines := strings.Split(string(data), "\n")
+	if len(lines) >= 1 {
+		v1.XPlanePath = strings.TrimSpace(lines[0])
+	}
+	if len(lines) >= 2 {
+		v1.Language = strings.TrimSpace(lines[1])
+	}
+	if len(lines) >= 3 {
+		v1.AG330Path = strings.TrimSpace(lines[2])
+	}
+	cfg.migrateV1(v1)
+	if err := cfg.save(); err != nil {
+		return cfg, err // 保留旧文件，下次启动再迁移
+	}
+	os.Remove(legacyPath)
+	return cfg, nil
+}
+
+// migrateV1 把旧版本的单个安装设置转换为默认配置档。
+func (c *appConfig) migrateV1(v1 legacyConfigV1) {
+	if v1.XPlanePath != "" || v1.Language != "" || v1.AG330Path != "" {
+		c.Profiles = append(c.Profiles, profileConfig{Name: defaultProfileName, XPlanePath: v1.XPlanePath, AG330Path: v1.AG330Path, Language: v1.Language})
+		c.ActiveProfile = defaultProfileName
+	}
+	for _, key := range []string{"xplane_path", "language", "ag330_path"} {
+		delete(c.raw, key)
+	}
+}
+
+// profile 按名称查找配置档。
+func (c *appConfig) profile(name string) *profileConfig {
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == name {
+			return &c.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// activeProfile 返回当前使用的配置档，没有任何配置档时创建默认配置档。
+func (c *appConfig) activeProfile() *profileConfig {
+	if p := c.profile(c.ActiveProfile); p != nil {
+		return p
+	}
+	if len(c.Profiles) == 0 {
+		c.Profiles = append(c.Profiles, profileConfig{Name: defaultProfileName})
+	}
+	c.ActiveProfile = c.Profiles[0].Name
+	return &c.Profiles[0]
+}
+
+// addProfile 添加一个新的配置档，名称不能为空或重复。
+func (c *appConfig) addProfile(p profileConfig) error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return errors.New("配置档名称不能为空")
+	}
+	if c.profile(p.Name) != nil {
+		return fmt.Errorf("配置档 '%s' 已存在", p.Name)
+	}
+	c.Profiles = append(c.Profiles, p)
+	return nil
+}
+
+// removeProfile 删除一个配置档，最后一个配置档不能删除。
+func (c *appConfig) removeProfile(name string) error {
+	if len(c.Profiles) <= 1 {
+		return errors.New("至少需要保留一个配置档")
+	}
+	kept := c.Profiles[:0]
+	for _, p := range c.Profiles {
+		if p.Name != name {
+			kept = append(kept, p)
+		}
+	}
+	c.Profiles = kept
+	if c.ActiveProfile == name {
+		c.ActiveProfile = c.Profiles[0].Name
+	}
+	return nil
+}
+
+// save 原子地写入配置文件，写到一半崩溃也不会损坏原配置。
+func (c *appConfig) save() error {
+	if c.saveErr != nil {
+		return c.saveErr
+	}
+	path, err := engine.DataPath(configFileName)
+	if err != nil {
+		return err
+	}
+	data, err := c.encode()
+	if err != nil {
+		return err
+	}
+	return engine.AtomicWriteFile(path, data, 0644)
+}
+
+// encode 把已知的设置合并到读取时的原始键值上再编码，未知的键因此得以保留。
+func (c *appConfig) encode() ([]byte, error) {
+	if c.SchemaVersion < configSchemaVersion {
+		c.SchemaVersion = configSchemaVersion
+	}
+	var known bytes.Buffer
+	if err := toml.NewEncoder(&known).Encode(c); err != nil {
+		return nil, err
+	}
+	var merged map[string]any
+	if err := toml.Unmarshal(known.Bytes(), &merged); err != nil {
+		return nil, err
+	}
+	merged = mergeConfigTables(c.raw, merged)
+
+	var buf bytes.Buffer
+	buf.WriteString("# AeroGennis A330-300 Installer 配置文件\n")
+	if err := toml.NewEncoder(&buf).Encode(merged); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mergeConfigTables 返回 base 与 override 合并后的新表，同名的键以 override 为准，子表递归合并。
+// 配置档数组按 name 对应合并，被删除的配置档不会保留。
+func mergeConfigTables(base, override map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(override))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		switch v := v.(type) {
+		case map[string]any:
+			if baseTable, ok := out[k].(map[string]any); ok {
+				out[k] = mergeConfigTables(baseTable, v)
+				continue
+			}
+		case []map[string]any:
+			if baseTables, ok := out[k].([]map[string]any); ok {
+				merged := make([]map[string]any, len(v))
+				for i, table := range v {
+					merged[i] = table
+					for _, baseTable := range baseTables {
+						if baseTable["name"] != nil && baseTable["name"] == table["name"] {
+							merged[i] = mergeConfigTables(baseTable, table)
+							break
+						}
+					}
+				}
+				out[k] = merged
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// readConfig 读取配置并应用下载重试和限速设置。读取失败时仍返回可用的配置，同时返回错误和警告供界面提示。
+func readConfig() (*appConfig, []configWarning, error) {
+	cfg, warnings, err := loadConfig()
+	applyDownloadConfig(cfg)
+	return cfg, warnings, err
+}
+
+// applyDownloadConfig 把下载设置应用到安装引擎，设置页修改后也调用它。
+func applyDownloadConfig(cfg *appConfig) {
+	engine.DownloadRetryPolicy = cfg.retryPolicy()
+	engine.DownloadLimiter.SetSchedule(cfg.bandwidthLimit(), cfg.bandwidthSchedule())
+}
+
+// writeConfig 把当前配置档的路径和语言写回配置文件。
+func writeConfig(state *AppState) error {
+	if state.config == nil {
+		state.config = defaultConfig()
+	}
+	p := state.config.activeProfile()
+	p.XPlanePath = state.xpPath
+	p.Language = state.language
+	p.AG330Path = state.ag330Path
+	return state.config.save()
+}
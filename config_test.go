@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"myapp/engine"
+)
+
+// TestCorruptConfigIsBackedUp 检查无法解析的配置文件在保存新设置前被备份，用户原来的配置档不会丢失。
+func TestCorruptConfigIsBackedUp(t *testing.T) {
+	path, err := engine.DataPath(configFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupt := []byte("active_profile = \"Stable\"\n[[profiles]\nname = \"Stable\"\n")
+	if err := os.WriteFile(path, corrupt, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		backups, _ := filepath.Glob(path + "*")
+		for _, p := range backups {
+			os.Remove(p)
+		}
+	})
+
+	cfg, _, err := loadConfig()
+	var corruptErr *corruptConfigError
+	if !errors.As(err, &corruptErr) {
+		t.Fatalf("loadConfig error = %v, want corruptConfigError", err)
+	}
+	if len(cfg.Profiles) != 0 {
+		t.Errorf("loadConfig returned profiles %v from a corrupt file", cfg.Profiles)
+	}
+	cfg.activeProfile()
+	if err := cfg.save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	backup, err := os.ReadFile(corruptErr.Backup)
+	if err != nil {
+		t.Fatalf("backup %s: %v", corruptErr.Backup, err)
+	}
+	if string(backup) != string(corrupt) {
+		t.Errorf("backup = %q, want the original file %q", backup, corrupt)
+	}
+	if _, _, err := loadConfig(); err != nil {
+		t.Errorf("config saved after the backup cannot be read: %v", err)
+	}
+}
+
+// TestConfigNotSavedWithoutBackup 检查无法备份损坏的配置文件时拒绝保存。
+func TestConfigNotSavedWithoutBackup(t *testing.T) {
+	cfg := defaultConfig()
+	err := backupCorruptConfig(filepath.Join(t.TempDir(), "missing", configFileName), cfg, errors.New("parse error"))
+	if err == nil {
+		t.Fatal("backupCorruptConfig succeeded without a file")
+	}
+	if err := cfg.save(); err == nil {
+		t.Error("save succeeded although the corrupt file was not backed up")
+	}
+}
+
+// TestConfigWarnings 检查被忽略的速度规则和较新的 schema_version 作为警告返回，配置本身仍然可用。
+func TestConfigWarnings(t *testing.T) {
+	path, err := engine.DataPath(configFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := "schema_version = 99\n\n[download]\n\n[[download.bandwidth_schedule]]\nstart = \"25:00\"\nend = \"08:00\"\nlimit_kbps = 512\n\n[[download.bandwidth_schedule]]\nstart = \"22:00\"\nend = \"06:00\"\nlimit_kbps = 0\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Remove(path) })
+
+	cfg, warnings, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	keys := make(map[string]bool)
+	for _, w := range warnings {
+		keys[w.key] = true
+	}
+	if len(warnings) != 2 || !keys["config_newer_schema_warning"] || !keys["config_rule_ignored_warning"] {
+		t.Errorf("warnings = %v, want one newer-schema and one ignored-rule warning", warnings)
+	}
+	if rules := cfg.bandwidthSchedule(); len(rules) != 1 {
+		t.Errorf("bandwidthSchedule kept %d rules, want the valid one", len(rules))
+	}
+}
+
+// TestInsecureCatalogSourceIgnored 检查配置中的 http 目录来源被忽略并给出警告，https 来源照常使用。
+func TestInsecureCatalogSourceIgnored(t *testing.T) {
+	path, err := engine.DataPath(configFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := "[[catalog_sources]]\nname = \"Plain\"\nlocation = \"http://example.com/liveries.json\"\n\n[[catalog_sources]]\nname = \"Mirror\"\nlocation = \"https://example.com/liveries.json\"\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Remove(path) })
+
+	cfg, warnings, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].key != "config_insecure_source_warning" {
+		t.Errorf("warnings = %v, want one insecure-source warning", warnings)
+	}
+	sources := enabledSources(cfg)
+	if len(sources) != 1 || sources[0].Name != "Mirror" {
+		t.Errorf("enabledSources = %v, want only the https source", sources)
+	}
+}
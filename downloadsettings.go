@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxConcurrencyOption 是设置页中可选的最大同时下载数。
+const maxConcurrencyOption = 8
+
+// createDownloadSection 是设置页面中的下载设置：同时下载数、自适应调整、合计速度上限和按时段的速度规则。
+func createDownloadSection(state *AppState) fyne.CanvasObject {
+	d := state.config.Download
+	var options []string
+	for i := 1; i <= maxConcurrencyOption; i++ {
+		options = append(options, strconv.Itoa(i))
+	}
+	concurrencySelect := widget.NewSelect(options, nil)
+	concurrencySelect.SetSelected(strconv.Itoa(state.config.concurrency().Workers))
+	adaptiveCheck := widget.NewCheck(state.tr("adaptive_concurrency_check"), nil)
+	adaptiveCheck.SetChecked(d.AdaptiveConcurrency)
+	limitEntry := widget.NewEntry()
+	limitEntry.SetText(strconv.Itoa(max(d.BandwidthLimitKBps, 0)))
+	limitEntry.SetPlaceHolder("0")
+
+	saveBtn := widget.NewButton(state.tr("save_download_settings_button"), func() {
+		limit, err := strconv.Atoi(strings.TrimSpace(limitEntry.Text))
+		if err != nil || limit < 0 {
+			dialog.ShowError(fmt.Errorf("%s", state.tr("bandwidth_limit_error")), state.mainWindow)
+			return
+		}
+		workers, _ := strconv.Atoi(concurrencySelect.Selected)
+		state.config.Download.Concurrency = workers
+		state.config.Download.AdaptiveConcurrency = adaptiveCheck.Checked
+		state.config.Download.BandwidthLimitKBps = limit
+		if saveDownloadSettings(state) {
+			dialog.ShowInformation(state.tr("save_success_title"), state.tr("download_settings_saved"), state.mainWindow)
+		}
+	})
+
+	rulesBox := container.NewVBox()
+	var refreshRules func()
+	refreshRules = func() {
+		rulesBox.RemoveAll()
+		if len(state.config.Download.BandwidthSchedule) == 0 {
+			rulesBox.Add(widget.NewLabel(state.tr("bandwidth_no_rules_label")))
+		}
+		for i, r := range state.config.Download.BandwidthSchedule {
+			deleteBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				rules := slices.Delete(slices.Clone(state.config.Download.BandwidthSchedule), i, i+1)
+				state.config.setBandwidthSchedule(rules)
+				saveDownloadSettings(state)
+				refreshRules()
+			})
+			rulesBox.Add(container.NewBorder(nil, nil, nil, deleteBtn, widget.NewLabel(bandwidthRuleText(state, r))))
+		}
+	}
+	refreshRules()
+	addRuleBtn := widget.NewButton(state.tr("bandwidth_add_rule_button"), func() {
+		handleAddBandwidthRule(state, refreshRules)
+	})
+	return container.NewVBox(
+		widget.NewLabelWithStyle(state.tr("download_settings_label"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewForm(
+			widget.NewFormItem(state.tr("concurrency_label"), concurrencySelect),
+			widget.NewFormItem("", adaptiveCheck),
+			widget.NewFormItem(state.tr("bandwidth_limit_label"), limitEntry),
+		),
+		saveBtn,
+		widget.NewLabel(state.tr("bandwidth_schedule_label")),
+		rulesBox,
+		addRuleBtn,
+	)
+}
+
+// saveDownloadSettings 把下载设置应用到引擎并保存，失败时显示错误并返回 false。
+func saveDownloadSettings(state *AppState) bool {
+	applyDownloadConfig(state.config)
+	if err := writeConfig(state); err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %w", state.tr("save_config_error"), err), state.mainWindow)
+		return false
+	}
+	return true
+}
+
+// bandwidthRuleText 返回规则在列表中的说明，例如 "22:00 – 07:00: 不限速"。
+func bandwidthRuleText(state *AppState, r bandwidthRuleConfig) string {
+	if r.LimitKBps <= 0 {
+		return state.tr("bandwidth_rule_unlimited", r.Start, r.End)
+	}
+	return state.tr("bandwidth_rule_limited", r.Start, r.End, r.LimitKBps)
+}
+
+// handleAddBandwidthRule 询问时段和速度上限，添加一条规则并立即生效。
+func handleAddBandwidthRule(state *AppState, onAdded func()) {
+	startEntry := widget.NewEntry()
+	startEntry.SetPlaceHolder("22:00")
+	endEntry := widget.NewEntry()
+	endEntry.SetPlaceHolder("07:00")
+	limitEntry := widget.NewEntry()
+	limitEntry.SetText("0")
+	items := []*widget.FormItem{
+		widget.NewFormItem(state.tr("bandwidth_rule_start_label"), startEntry),
+		widget.NewFormItem(state.tr("bandwidth_rule_end_label"), endEntry),
+		widget.NewFormItem(state.tr("bandwidth_limit_label"), limitEntry),
+	}
+	dialog.ShowForm(state.tr("bandwidth_add_rule_button"), state.tr("confirm_button"), state.tr("cancel_button"), items, func(confirm bool) {
+		if !confirm {
+			return
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(limitEntry.Text))
+		if err != nil || limit < 0 {
+			dialog.ShowError(fmt.Errorf("%s", state.tr("bandwidth_limit_error")), state.mainWindow)
+			return
+		}
+		r := bandwidthRuleConfig{Start: strings.TrimSpace(startEntry.Text), End: strings.TrimSpace(endEntry.Text), LimitKBps: limit}
+		if _, err := r.rule(); err != nil {
+			dialog.ShowError(fmt.Errorf("%s", state.tr("bandwidth_rule_time_error")), state.mainWindow)
+			return
+		}
+		state.config.setBandwidthSchedule(append(state.config.Download.BandwidthSchedule, r))
+		if saveDownloadSettings(state) {
+			onAdded()
+		}
+	}, state.mainWindow)
+}
@@ -0,0 +1,31 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// AtomicWriteFile 把 data 写入 path 旁边的临时文件并同步到磁盘，再改名替换 path，
+// 写到一半崩溃或断电时 path 仍是完整的旧内容或新内容。改名后同步所在目录（Windows 上不需要）。
+func AtomicWriteFile(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package engine
+
+import "os"
+
+// syncDir 同步目录，让改名在断电后也能保留。
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
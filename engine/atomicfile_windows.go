@@ -0,0 +1,6 @@
+package engine
+
+// syncDir 在 Windows 上什么也不做：目录无法打开为文件同步，NTFS 的改名由文件系统日志保证。
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"sync"
+	"time"
+)
+
+// Concurrency 是队列同时安装的任务数设置。
+type Concurrency struct {
+	Workers int // 固定的并发数；自适应时为初始值
+	// Adaptive 为 true 时按测得的下载吞吐量和错误率在 1 到 MaxWorkers 之间调整并发数：
+	// 吞吐量明显上升时加一个线程，出现重试或失败、或吞吐量明显下降时减一个线程。
+	Adaptive   bool
+	MaxWorkers int
+}
+
+// adaptInterval 是自适应调整的测量周期。
+const adaptInterval = 5 * time.Second
+
+// concurrencyController 统计队列运行时的下载字节数和错误数，并决定目标线程数。
+type concurrencyController struct {
+	mu         sync.Mutex
+	target     int
+	maxWorkers int
+	active     int
+	bytes      int64
+	errors     int
+	lastBytes  map[string]int64 // 每个任务上次报告的已下载字节数
+	best       float64          // 当前并发数下测得的最高吞吐量（字节/秒）
+}
+
+func newConcurrencyController(c Concurrency) *concurrencyController {
+	workers := max(c.Workers, 1)
+	maxWorkers := workers
+	if c.Adaptive {
+		maxWorkers = max(c.MaxWorkers, workers)
+	}
+	return &concurrencyController{target: workers, maxWorkers: maxWorkers, lastBytes: make(map[string]int64)}
+}
+
+// observe 返回统计下载进度和错误后再转发给 sink 的 Sink。
+func (c *concurrencyController) observe(sink Sink) Sink {
+	return func(e Event) {
+		c.mu.Lock()
+		switch e.Phase {
+		case PhaseDownload:
+			// 续传时第一次报告的字节数包含以前下载的部分，从续传位置开始统计
+			last, seen := c.lastBytes[e.Item]
+			if !seen {
+				last = e.ResumedFrom
+			}
+			if delta := e.Bytes - last; delta > 0 && e.Bytes >= e.ResumedFrom {
+				c.bytes += delta
+			}
+			c.lastBytes[e.Item] = e.Bytes
+		case PhaseRetry, PhaseError:
+			c.errors++
+		}
+		c.mu.Unlock()
+		sink.emit(e)
+	}
+}
+
+// adjust 根据一个周期的吞吐量和错误数调整目标线程数，返回新的目标。
+// 设置了速度上限且已接近上限时不再增加线程，多开线程只会互相分走带宽。
+func (c *concurrencyController) adjust(elapsed time.Duration) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	throughput := float64(c.bytes) / elapsed.Seconds()
+	errors := c.errors
+	c.bytes, c.errors = 0, 0
+	limit := float64(DownloadLimiter.Rate())
+	capped := limit > 0 && throughput >= limit*0.9
+	switch {
+	case errors > 0:
+		c.target = max(c.target-1, 1)
+		c.best = 0
+	case throughput > c.best*1.1 && !capped:
+		c.best = throughput
+		c.target = min(c.target+1, c.maxWorkers)
+	case throughput < c.best*0.8:
+		c.target = max(c.target-1, 1)
+		c.best = throughput
+	}
+	return c.target
+}
+
+// start 在目标线程数大于正在运行的线程数时登记一个新线程并返回 true。
+// 已经没有线程在运行时返回 false，此时队列正在结束。
+// wg 在同一把锁内加一，最后一个线程退出时 wg.Wait 不会漏掉正在启动的线程。
+func (c *concurrencyController) start(initial bool, wg *sync.WaitGroup) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.active >= c.target || (!initial && c.active == 0) {
+		return false
+	}
+	c.active++
+	wg.Add(1)
+	return true
+}
+
+// keep 在线程取下一个任务前调用：线程数超过目标时登记退出并返回 false。
+func (c *concurrencyController) keep() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.active > c.target {
+		c.active--
+		return false
+	}
+	return true
+}
+
+// stop 登记一个因为没有任务而退出的线程。
+func (c *concurrencyController) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active--
+}
@@ -0,0 +1,16 @@
+package engine
+
+import "testing"
+
+// TestObserveResumedDownload 检查续传的下载只把本次下载的字节计入吞吐量，不把已下载的部分算作一次突增。
+func TestObserveResumedDownload(t *testing.T) {
+	c := newConcurrencyController(Concurrency{Workers: 2, Adaptive: true, MaxWorkers: 4})
+	sink := c.observe(nil)
+	const resumed = 800 << 20
+	sink(Event{Phase: PhaseDownload, Item: "resumed", Bytes: resumed + 1<<20, TotalBytes: 1 << 30, ResumedFrom: resumed})
+	sink(Event{Phase: PhaseDownload, Item: "resumed", Bytes: resumed + 3<<20, TotalBytes: 1 << 30, ResumedFrom: resumed})
+	sink(Event{Phase: PhaseDownload, Item: "fresh", Bytes: 2 << 20, TotalBytes: 1 << 30})
+	if want := int64(5 << 20); c.bytes != want {
+		t.Errorf("counted %d bytes, want %d", c.bytes, want)
+	}
+}
@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Steam 中 X-Plane 12 所在的目录名。
+const steamXPlaneFolder = "X-Plane 12"
+
+// vdfPathPattern 匹配 libraryfolders.vdf 中的 "path" "D:\\SteamLibrary" 行。
+var vdfPathPattern = regexp.MustCompile(`^\s*"path"\s+"((?:[^"\\]|\\.)*)"`)
+
+// readInstallRecord 读取 x-plane_install_12.txt，X-Plane 安装程序每安装一次就写入一行路径。
+func readInstallRecord(data []byte) []string {
+	var paths []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths
+}
+
+// parseSteamLibraryFolders 取出 libraryfolders.vdf 中所有游戏库的路径。
+func parseSteamLibraryFolders(data []byte) []string {
+	var libraries []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		m := vdfPathPattern.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		libraries = append(libraries, strings.ReplaceAll(m[1], `\\`, `\`))
+	}
+	return libraries
+}
+
+// DiscoverXPlaneInstalls 从 X-Plane 的安装记录和 Steam 游戏库中查找 X-Plane 12，
+// 只返回通过 ValidateXPlaneDir 校验的目录，按发现顺序去重。
+func DiscoverXPlaneInstalls() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var candidates []string
+	for _, file := range xplaneInstallRecordFiles(home) {
+		if data, err := os.ReadFile(file); err == nil {
+			candidates = append(candidates, readInstallRecord(data)...)
+		}
+	}
+	for _, file := range steamLibraryFolderFiles(home) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, library := range parseSteamLibraryFolders(data) {
+			candidates = append(candidates, filepath.Join(library, "steamapps", "common", steamXPlaneFolder))
+		}
+	}
+
+	var found []string
+	for _, candidate := range candidates {
+		candidate = filepath.Clean(candidate)
+		if resolved, err := filepath.EvalSymlinks(candidate); err == nil {
+			candidate = resolved
+		}
+		duplicate := false
+		for _, f := range found {
+			if SamePath(f, candidate) {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+		if valid, _ := ValidateXPlaneDir(candidate); valid {
+			found = append(found, candidate)
+		}
+	}
+	return found
+}
+
+// FindInstalledAircraft 依次检查手动指定的路径、默认安装位置和 Aircraft 下名字含 AeroGennis 的目录。
+func FindInstalledAircraft(xpPath, ag330Path string) (string, bool) {
+	var candidates []string
+	if ag330Path != "" {
+		candidates = append(candidates, ag330Path)
+	}
+	if xpPath != "" {
+		target, _, _ := AircraftInstallPaths(xpPath)
+		candidates = append(candidates, target)
+		if foundPath, err := findAerogennisDir(xpPath); err == nil {
+			candidates = append(candidates, foundPath)
+		}
+	}
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			continue
+		}
+		if VerifyAircraftDir(path) == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// ValidateXPlaneDir 检查 path 是否为 X-Plane 12 的根目录，返回缺少的项目。
+func ValidateXPlaneDir(path string) (bool, []string) {
+	requiredItems := []string{"Aircraft", "Custom Scenery", "Global Scenery", "Resources"}
+	var missingItems []string
+	if !filepath.IsAbs(path) {
+		return false, []string{"Path must be an absolute directory path."}
+	}
+	for _, item := range requiredItems {
+		if _, err := os.Stat(filepath.Join(path, item)); os.IsNotExist(err) {
+			missingItems = append(missingItems, item)
+		}
+	}
+	// X-Plane 主程序的名字因平台而异，存在其中一个即可
+	hasExecutable := false
+	for _, item := range xplaneExecutables {
+		if pathExists(filepath.Join(path, item)) {
+			hasExecutable = true
+			break
+		}
+	}
+	if !hasExecutable {
+		missingItems = append(missingItems, strings.Join(xplaneExecutables, " / "))
+	}
+	return len(missingItems) == 0, missingItems
+}
+
+func findAerogennisDir(basePath string) (string, error) {
+	aircraftPath := filepath.Join(basePath, "Aircraft")
+	entries, err := os.ReadDir(aircraftPath)
+	if err != nil {
+		return "", fmt.Errorf("could not read Aircraft directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.Contains(strings.ToLower(entry.Name()), "aerogennis") {
+			return filepath.Join(aircraftPath, entry.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("no directory containing 'Aerogennis' found in '%s'", aircraftPath)
+}
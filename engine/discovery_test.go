@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// makeXPlaneDir 在 root 下伪造一个 X-Plane 12 目录：必需的文件夹和主程序，skip 中的项目不创建。
+func makeXPlaneDir(t *testing.T, root string, executable string, skip ...string) string {
+	t.Helper()
+	for _, dir := range []string{"Aircraft", "Custom Scenery", "Global Scenery", "Resources"} {
+		if slices.Contains(skip, dir) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if executable != "" {
+		if err := os.WriteFile(filepath.Join(root, executable), nil, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// foreignExecutable 返回其它平台的 X-Plane 主程序名。
+func foreignExecutable() string {
+	if runtime.GOOS == "windows" {
+		return "X-Plane-x86_64"
+	}
+	return "X-Plane.exe"
+}
+
+// setHome 让 os.UserHomeDir 返回 home。
+func setHome(t *testing.T, home string) {
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateXPlaneDir(t *testing.T) {
+	tests := []struct {
+		name       string
+		executable string
+		skip       []string
+		missing    []string
+	}{
+		{name: "valid", executable: xplaneExecutables[0]},
+		{name: "missing Resources", executable: xplaneExecutables[0], skip: []string{"Resources"}, missing: []string{"Resources"}},
+		{name: "wrong platform executable", executable: foreignExecutable(), missing: []string{strings.Join(xplaneExecutables, " / ")}},
+		{name: "no executable", missing: []string{strings.Join(xplaneExecutables, " / ")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := makeXPlaneDir(t, t.TempDir(), tt.executable, tt.skip...)
+			valid, missing := ValidateXPlaneDir(dir)
+			if valid != (len(tt.missing) == 0) || !slices.Equal(missing, tt.missing) {
+				t.Errorf("ValidateXPlaneDir = %v, %q; want missing %q", valid, missing, tt.missing)
+			}
+		})
+	}
+
+	if valid, _ := ValidateXPlaneDir("X-Plane 12"); valid {
+		t.Error("relative path accepted")
+	}
+}
+
+func TestReadInstallRecord(t *testing.T) {
+	data := "/games/X-Plane 12\r\n\n  /old/X-Plane 12  \n/games/X-Plane 12\n"
+	got := readInstallRecord([]byte(data))
+	want := []string{"/games/X-Plane 12", "/old/X-Plane 12", "/games/X-Plane 12"}
+	if !slices.Equal(got, want) {
+		t.Errorf("readInstallRecord = %q, want %q", got, want)
+	}
+}
+
+func TestParseSteamLibraryFolders(t *testing.T) {
+	data := `"libraryfolders"
+{
+	"0"
+	{
+		"path"		"C:\\Program Files (x86)\\Steam"
+		"label"		""
+		"apps"
+		{
+			"2014780"		"123"
+		}
+	}
+	"1"
+	{
+		"path"		"/home/pilot/SteamLibrary"
+	}
+}
+`
+	got := parseSteamLibraryFolders([]byte(data))
+	want := []string{`C:\Program Files (x86)\Steam`, "/home/pilot/SteamLibrary"}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseSteamLibraryFolders = %q, want %q", got, want)
+	}
+}
+
+// TestDiscoverXPlaneInstalls 用伪造的主目录检查安装记录中过期、重复的路径和 Steam 游戏库。
+func TestDiscoverXPlaneInstalls(t *testing.T) {
+	home := t.TempDir()
+	setHome(t, home)
+	games := t.TempDir()
+	current := makeXPlaneDir(t, filepath.Join(games, "X-Plane 12"), xplaneExecutables[0])
+	stale := filepath.Join(games, "Deleted X-Plane 12")
+	broken := makeXPlaneDir(t, filepath.Join(games, "Broken"), xplaneExecutables[0], "Resources")
+	library := filepath.Join(games, "SteamLibrary")
+	steam := makeXPlaneDir(t, filepath.Join(library, "steamapps", "common", steamXPlaneFolder), xplaneExecutables[0])
+
+	record := strings.Join([]string{stale, current, broken, current + string(filepath.Separator), current}, "\n")
+	writeFile(t, xplaneInstallRecordFiles(home)[0], record)
+	vdf := "\"libraryfolders\"\n{\n\t\"0\"\n\t{\n\t\t\"path\"\t\t\"" + strings.ReplaceAll(library, `\`, `\\`) + "\"\n\t}\n}\n"
+	writeFile(t, steamLibraryFolderFiles(home)[0], vdf)
+
+	got := DiscoverXPlaneInstalls()
+	want := []string{current, steam}
+	for i := range want {
+		if resolved, err := filepath.EvalSymlinks(want[i]); err == nil {
+			want[i] = resolved
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("DiscoverXPlaneInstalls = %q, want %q", got, want)
+	}
+}
+
+func TestDiscoverXPlaneInstallsWithoutRecords(t *testing.T) {
+	setHome(t, t.TempDir())
+	if got := DiscoverXPlaneInstalls(); len(got) != 0 {
+		t.Errorf("DiscoverXPlaneInstalls = %q, want none", got)
+	}
+}
+
+func TestSelfUninstallScript(t *testing.T) {
+	dir := t.TempDir()
+	exePath := filepath.Join(dir, "Pilot's Installer")
+	dataFiles := []string{filepath.Join(dir, "config.toml"), filepath.Join(dir, "receipts.json")}
+	dataDirs := []string{filepath.Join(dir, "previews")}
+	script := selfUninstallScript(exePath, dataFiles, dataDirs)
+
+	lines := strings.Split(strings.TrimSpace(script), "\n")
+	quote := func(p string) string { return `"` + p + `"` }
+	remove, removeDir, removeSelf := "del ", "rmdir /s /q ", `(goto) 2>nul & del "%~f0"`
+	if runtime.GOOS != "windows" {
+		quote = func(p string) string { return "'" + strings.ReplaceAll(p, "'", `'\''`) + "'" }
+		remove, removeDir, removeSelf = "rm -f ", "rm -rf ", `rm -f "$0"`
+	}
+	var want []string
+	for _, p := range dataFiles {
+		want = append(want, remove+quote(p))
+	}
+	for _, p := range dataDirs {
+		want = append(want, removeDir+quote(p))
+	}
+	want = append(want, remove+quote(exePath), removeSelf)
+	// 第一行是脚本头，第二行等待程序退出
+	if len(lines) < 2 || !slices.Equal(lines[len(lines)-len(want):], want) {
+		t.Errorf("selfUninstallScript =\n%s\nwant to end with\n%s", script, strings.Join(want, "\n"))
+	}
+	if runtime.GOOS != "windows" && !strings.Contains(script, `'\''`) {
+		t.Errorf("single quote in %q not escaped:\n%s", exePath, script)
+	}
+}
+
+func TestSelfUninstallScriptAppBundle(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip(".app bundles only exist on macOS")
+	}
+	bundle := filepath.Join(t.TempDir(), "Installer.app")
+	exePath := filepath.Join(bundle, "Contents", "MacOS", "Installer")
+	script := selfUninstallScript(exePath, nil, nil)
+	if !strings.Contains(script, "rm -rf '"+bundle+"'\n") || strings.Contains(script, exePath) {
+		t.Errorf("selfUninstallScript should remove the whole bundle %s:\n%s", bundle, script)
+	}
+}
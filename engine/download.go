@@ -0,0 +1,349 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// resumeState 是与 .part 文件放在一起的断点续传记录。
+type resumeState struct {
+	URL          string `json:"url"`
+	SHA256       string `json:"sha256,omitempty"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	TotalBytes   int64  `json:"total_bytes"`
+}
+
+// CacheDir 返回程序目录下的下载缓存目录，中断的下载在这里续传。
+func CacheDir() (string, error) {
+	dir, err := DataPath("downloads")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// LiveryCachePath 为每个涂装链接生成固定的缓存文件名，以便重启后继续下载。
+func LiveryCachePath(cacheDir, url string) string {
+	return filepath.Join(cacheDir, fmt.Sprintf("livery_%x.zip", sha1.Sum([]byte(url))))
+}
+
+func partPaths(destPath string) (partPath, statePath string) {
+	return destPath + ".part", destPath + ".part.json"
+}
+
+func loadResumeState(statePath string) *resumeState {
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return nil
+	}
+	var s resumeState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil
+	}
+	return &s
+}
+
+func saveResumeState(statePath string, s *resumeState) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath, data, 0644)
+}
+
+// RemovePartial 删除未完成的下载及其续传记录。
+func RemovePartial(destPath string) {
+	partPath, statePath := partPaths(destPath)
+	os.Remove(partPath)
+	os.Remove(statePath)
+}
+
+// parseContentRange 解析 "bytes start-end/total"，total 为 * 时返回 -1。
+func parseContentRange(header string) (start, total int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rangePart, totalPart, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+	startPart, _, found := strings.Cut(rangePart, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if totalPart == "*" {
+		return start, -1, true
+	}
+	total, err = strconv.ParseInt(totalPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, total, true
+}
+
+// checkDownloadURL 只允许 https 链接。链接可以来自任何涂装目录来源，包的内容由 Source 公布的 SHA-256 校验。
+func checkDownloadURL(url string) error {
+	if !strings.HasPrefix(url, "https://") {
+		return fmt.Errorf("%w: %s", ErrInvalidURL, url)
+	}
+	return nil
+}
+
+// downloadResumable 把 src 下载到 destPath。数据先写入 destPath.part，
+// 旁边的 .part.json 记录 ETag/Last-Modified，重试或重启后用 Range/If-Range 续传；
+// 服务器忽略 Range 或校验值已变化时从头下载。下载过程中同时计算 SHA-256，
+// 与 src 公布的值不一致时删除文件并返回 IntegrityError。每读到一块数据发送一个 PhaseDownload 事件。
+// 读取速度受 DownloadLimiter 限制。返回这次下载的响应中的 ETag 和 Last-Modified。
+func downloadResumable(ctx context.Context, src Source, destPath string, sink Sink) (RemotePackageInfo, error) {
+	url := src.URL
+	if err := checkDownloadURL(url); err != nil {
+		return RemotePackageInfo{}, err
+	}
+
+	partPath, statePath := partPaths(destPath)
+	var offset int64
+	prev := loadResumeState(statePath)
+	if prev != nil && prev.URL == url && prev.SHA256 == NormalizeSHA256(src.SHA256) && (prev.ETag != "" || prev.LastModified != "") {
+		if info, err := os.Stat(partPath); err == nil {
+			offset = info.Size()
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return RemotePackageInfo{}, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		// 弱 ETag 不能用于 If-Range
+		if prev.ETag != "" && !strings.HasPrefix(prev.ETag, "W/") {
+			req.Header.Set("If-Range", prev.ETag)
+		} else if prev.LastModified != "" {
+			req.Header.Set("If-Range", prev.LastModified)
+		}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return RemotePackageInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	totalBytes := resp.ContentLength
+	switch {
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		start, total, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			// 服务器返回的区间与本地不一致，放弃续传
+			resp.Body.Close()
+			RemovePartial(destPath)
+			return downloadResumable(ctx, src, destPath, sink)
+		}
+		totalBytes = total
+	case offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		resp.Body.Close()
+		if prev.TotalBytes > 0 && prev.TotalBytes == offset {
+			// 上次已经下载完整，只是没来得及校验和改名
+			return finishDownload(src, destPath, offset, RemotePackageInfo{ETag: prev.ETag, LastModified: prev.LastModified, Size: offset})
+		}
+		RemovePartial(destPath)
+		return downloadResumable(ctx, src, destPath, sink)
+	case resp.StatusCode == http.StatusOK:
+		// 服务器忽略了 Range，或文件已变化，从头开始
+		offset = 0
+	default:
+		return RemotePackageInfo{}, newHTTPStatusError(resp)
+	}
+
+	if err := src.checkSize(totalBytes); err != nil {
+		RemovePartial(destPath)
+		return RemotePackageInfo{}, err
+	}
+
+	state := &resumeState{
+		URL:          url,
+		SHA256:       NormalizeSHA256(src.SHA256),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		TotalBytes:   totalBytes,
+	}
+	if offset > 0 {
+		if state.ETag == "" {
+			state.ETag = prev.ETag
+		}
+		if state.LastModified == "" {
+			state.LastModified = prev.LastModified
+		}
+	}
+	if state.ETag != "" || state.LastModified != "" {
+		if err := saveResumeState(statePath, state); err != nil {
+			return RemotePackageInfo{}, err
+		}
+	} else {
+		// 没有校验值无法安全续传
+		os.Remove(statePath)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if offset > 0 {
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return RemotePackageInfo{}, err
+	}
+	defer file.Close()
+
+	// 续传时先补算已下载部分的哈希
+	hasher := sha256.New()
+	if offset > 0 {
+		if err := hashFile(hasher, partPath); err != nil {
+			return RemotePackageInfo{}, err
+		}
+	}
+
+	// 使用 bufio.Writer 优化写入性能
+	writer := bufio.NewWriter(io.MultiWriter(file, hasher))
+
+	// 优化缓冲区大小为 64KB
+	buf := make([]byte, 64*1024)
+	downloadedBytes := offset
+	startTime := time.Now()
+	sink.emit(Event{Phase: PhaseDownload, Path: destPath, Bytes: downloadedBytes, TotalBytes: totalBytes, ResumedFrom: offset})
+	body := limitedReader{ctx: ctx, r: resp.Body, limiter: DownloadLimiter}
+	for {
+		n, readErr := body.Read(buf)
+		if n > 0 {
+			if _, writeErr := writer.Write(buf[0:n]); writeErr != nil {
+				return RemotePackageInfo{}, writeErr
+			}
+			downloadedBytes += int64(n)
+			speed := float64(downloadedBytes-offset) / time.Since(startTime).Seconds() / (1024 * 1024)
+			sink.emit(Event{Phase: PhaseDownload, Path: destPath, Bytes: downloadedBytes, TotalBytes: totalBytes, ResumedFrom: offset, Speed: speed})
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			writer.Flush()
+			return RemotePackageInfo{}, readErr
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return RemotePackageInfo{}, err
+	}
+	if totalBytes > 0 && downloadedBytes != totalBytes {
+		return RemotePackageInfo{}, fmt.Errorf("下载不完整: %d / %d 字节: %w", downloadedBytes, totalBytes, io.ErrUnexpectedEOF)
+	}
+	if err := file.Close(); err != nil {
+		return RemotePackageInfo{}, err
+	}
+	if err := src.verify(downloadedBytes, hex.EncodeToString(hasher.Sum(nil))); err != nil {
+		RemovePartial(destPath)
+		return RemotePackageInfo{}, err
+	}
+	os.Remove(statePath)
+	if err := os.Rename(partPath, destPath); err != nil {
+		return RemotePackageInfo{}, err
+	}
+	return RemotePackageInfo{ETag: state.ETag, LastModified: state.LastModified, Size: downloadedBytes}, nil
+}
+
+// finishDownload 校验一个已完整下载的 .part 文件并改名为目标文件。
+func finishDownload(src Source, destPath string, size int64, info RemotePackageInfo) (RemotePackageInfo, error) {
+	partPath, statePath := partPaths(destPath)
+	sum, err := FileSHA256(partPath)
+	if err != nil {
+		return RemotePackageInfo{}, err
+	}
+	if err := src.verify(size, sum); err != nil {
+		RemovePartial(destPath)
+		return RemotePackageInfo{}, err
+	}
+	os.Remove(statePath)
+	if err := os.Rename(partPath, destPath); err != nil {
+		return RemotePackageInfo{}, err
+	}
+	return info, nil
+}
+
+// keepDownload 在 destPath 旁边记录已下载完的文件来自哪个链接、内容的哈希和下载时的 ETag/Last-Modified，
+// 以便安装失败后再次安装时直接使用，不必重新下载。
+func keepDownload(src Source, destPath string, info RemotePackageInfo) error {
+	return saveResumeState(destPath+".json", &resumeState{
+		URL:          src.URL,
+		SHA256:       NormalizeSHA256(src.SHA256),
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+		TotalBytes:   info.Size,
+	})
+}
+
+// keptDownload 返回 keepDownload 记录过、仍然可以使用的 destPath：只有 src 公布了 SHA-256、
+// 记录的链接和哈希与 src 一致、且文件内容与该哈希一致时才使用，否则需要重新下载。
+func keptDownload(src Source, destPath string) (RemotePackageInfo, bool) {
+	sum := NormalizeSHA256(src.SHA256)
+	if sum == "" {
+		return RemotePackageInfo{}, false
+	}
+	s := loadResumeState(destPath + ".json")
+	if s == nil || s.URL != src.URL || s.SHA256 != sum {
+		return RemotePackageInfo{}, false
+	}
+	if actual, err := FileSHA256(destPath); err != nil || actual != sum {
+		return RemotePackageInfo{}, false
+	}
+	return RemotePackageInfo{ETag: s.ETag, LastModified: s.LastModified, Size: s.TotalBytes}, true
+}
+
+// Download 按 DownloadRetryPolicy 把 src 下载到 destPath，支持断点续传。
+// 下载进度每 100ms 最多发送一次，每次准备重试前发送 PhaseRetry 事件。
+// ctx 被取消时删除未完成的 .part 文件并返回 ctx.Err()，因 ErrPaused 取消时保留 .part 以便继续；
+// 其它失败也保留 .part 以便下次续传。src 没有 SHA-256 时下载完成后发送 PhaseUnverified 事件。
+// 返回下载所用响应中的 ETag 和 Last-Modified，即实际下载到的那个版本的标识。
+func Download(ctx context.Context, src Source, destPath string, sink Sink) (RemotePackageInfo, error) {
+	sink = sink.throttle(100 * time.Millisecond)
+	policy := DownloadRetryPolicy
+	var info RemotePackageInfo
+	err := policy.Run(ctx, func() error {
+		var err error
+		info, err = downloadResumable(ctx, src, destPath, sink)
+		return err
+	}, func(a RetryAttempt) {
+		sink.emit(Event{Phase: PhaseRetry, Path: destPath, Attempt: a.Attempt, MaxAttempts: policy.MaxAttempts, Delay: a.Delay, Err: a.Err})
+	})
+	if err != nil && ctx.Err() != nil {
+		if !errors.Is(context.Cause(ctx), ErrPaused) {
+			RemovePartial(destPath)
+		}
+		return RemotePackageInfo{}, ctx.Err()
+	}
+	if err != nil {
+		return RemotePackageInfo{}, err
+	}
+	if NormalizeSHA256(src.SHA256) == "" {
+		sink.emit(Event{Phase: PhaseUnverified, Path: destPath})
+	}
+	return info, nil
+}
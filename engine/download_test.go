@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// liveryZip 返回只有一个涂装文件夹的压缩包。
+func liveryZip(t *testing.T, folder string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(folder + "/objects/fuselage.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("paint"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// serveTLS 启动一个用自签名证书的 https 服务器，测试期间 http.DefaultClient 信任它。
+func serveTLS(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+	client := http.DefaultClient
+	http.DefaultClient = srv.Client()
+	t.Cleanup(func() { http.DefaultClient = client })
+	return srv
+}
+
+// TestInstallLiveryFromOtherHost 安装来自非默认来源（任意 https 主机）的涂装，内容按目录中的 sha256 校验。
+func TestInstallLiveryFromOtherHost(t *testing.T) {
+	data := liveryZip(t, "Private Air")
+	sum := sha256.Sum256(data)
+	srv := serveTLS(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+
+	liveryDir := t.TempDir()
+	url := srv.URL + "/liveries/private-air.zip"
+	l := LiveryPackage{ID: "private-air", Name: "Private Air", URL: url, Source: Source{URL: url, SHA256: hex.EncodeToString(sum[:]), Size: int64(len(data))}}
+	if err := InstallLivery(context.Background(), l, []string{liveryDir}, nil); err != nil {
+		t.Fatalf("InstallLivery: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(liveryDir, "Private Air", "objects", "fuselage.png")); err != nil {
+		t.Errorf("livery not installed: %v", err)
+	}
+}
+
+func TestInstallLiveryHashMismatch(t *testing.T) {
+	data := liveryZip(t, "Private Air")
+	srv := serveTLS(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+
+	liveryDir := t.TempDir()
+	url := srv.URL + "/liveries/tampered.zip"
+	l := LiveryPackage{ID: "tampered", Name: "Tampered", URL: url, Source: Source{URL: url, SHA256: hex.EncodeToString(make([]byte, sha256.Size))}}
+	err := InstallLivery(context.Background(), l, []string{liveryDir}, nil)
+	var integrityErr *IntegrityError
+	if !errors.As(err, &integrityErr) {
+		t.Fatalf("InstallLivery = %v, want IntegrityError", err)
+	}
+	if entries, _ := os.ReadDir(liveryDir); len(entries) != 0 {
+		t.Errorf("liveries folder not empty after a failed install: %v", entries)
+	}
+}
+
+func TestDownloadRejectsPlainHTTP(t *testing.T) {
+	_, err := downloadResumable(context.Background(), Source{URL: "http://example.com/livery.zip"}, filepath.Join(t.TempDir(), "livery.zip"), nil)
+	if !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("downloadResumable = %v, want ErrInvalidURL", err)
+	}
+}
+
+// TestDownloadReportsUnverified 检查没有公布 SHA-256 的下载会发送 PhaseUnverified 事件。
+func TestDownloadReportsUnverified(t *testing.T) {
+	data := []byte("package")
+	sum := sha256.Sum256(data)
+	srv := serveTLS(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	for _, src := range []Source{{URL: srv.URL + "/unverified.zip"}, {URL: srv.URL + "/verified.zip", SHA256: hex.EncodeToString(sum[:])}} {
+		var unverified bool
+		sink := func(e Event) {
+			if e.Phase == PhaseUnverified {
+				unverified = true
+			}
+		}
+		if _, err := Download(context.Background(), src, filepath.Join(t.TempDir(), "package.zip"), sink); err != nil {
+			t.Fatalf("Download(%s): %v", src.URL, err)
+		}
+		if want := src.SHA256 == ""; unverified != want {
+			t.Errorf("Download(%s) unverified event = %v, want %v", src.URL, unverified, want)
+		}
+	}
+}
+
+// TestDownloadReturnsResponseValidators 检查 Download 返回实际下载响应中的 ETag 和 Last-Modified。
+func TestDownloadReturnsResponseValidators(t *testing.T) {
+	data := []byte("aircraft")
+	srv := serveTLS(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jun 2025 10:00:00 GMT")
+		w.Write(data)
+	}))
+	info, err := Download(context.Background(), Source{URL: srv.URL + "/aircraft.zip"}, filepath.Join(t.TempDir(), "aircraft.zip"), nil)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	want := RemotePackageInfo{ETag: `"v2"`, LastModified: "Mon, 02 Jun 2025 10:00:00 GMT", Size: int64(len(data))}
+	if info != want {
+		t.Errorf("Download = %+v, want %+v", info, want)
+	}
+}
+
+// TestUpdateLiveryRemovesStaleFiles 检查更新涂装时删除旧版本有而新版本没有的文件，并保留用户自己添加的文件。
+func TestUpdateLiveryRemovesStaleFiles(t *testing.T) {
+	v2 := liveryZip(t, "Update Air")
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"Update Air/objects/fuselage.png", "Update Air/objects/old.png"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte("paint"))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	versions := [][]byte{buf.Bytes(), v2}
+	srv := serveTLS(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(versions[0])
+	}))
+
+	liveryDir := t.TempDir()
+	t.Cleanup(func() { UpdateReceipts(func(db *ReceiptDB) { db.Remove(LiveryPackageID("update-air"), liveryDir) }) })
+	url := srv.URL + "/liveries/update-air.zip"
+	l := LiveryPackage{ID: "update-air", Name: "Update Air", URL: url, Source: Source{URL: url}}
+	if err := InstallLivery(context.Background(), l, []string{liveryDir}, nil); err != nil {
+		t.Fatalf("install v1: %v", err)
+	}
+	userFile := filepath.Join(liveryDir, "Update Air", "objects", "custom.png")
+	if err := os.WriteFile(userFile, []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	versions[0] = versions[1]
+	if err := InstallLivery(context.Background(), l, []string{liveryDir}, nil); err != nil {
+		t.Fatalf("install v2: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(liveryDir, "Update Air", "objects", "old.png")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file dropped in v2 still present: %v", err)
+	}
+	if _, err := os.Stat(userFile); err != nil {
+		t.Errorf("user file removed: %v", err)
+	}
+	db, err := LoadReceipts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := db.Find(LiveryPackageID("update-air"), liveryDir)
+	if r == nil || len(r.Files) != 1 {
+		t.Errorf("receipt after update = %+v, want one file", r)
+	}
+}
+
+// TestDownloadAircraftReusesKeptPackage 检查安装失败后保留的飞机包在哈希一致时直接使用，不再下载。
+func TestDownloadAircraftReusesKeptPackage(t *testing.T) {
+	data := []byte("aircraft package")
+	sum := sha256.Sum256(data)
+	requests := 0
+	srv := serveTLS(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"a1"`)
+		w.Write(data)
+	}))
+	src := Source{URL: srv.URL + "/aircraft.zip", SHA256: hex.EncodeToString(sum[:])}
+	pkg, err := DownloadAircraft(context.Background(), src, nil)
+	if err != nil {
+		t.Fatalf("first download: %v", err)
+	}
+	t.Cleanup(pkg.Remove)
+	again, err := DownloadAircraft(context.Background(), src, nil)
+	if err != nil {
+		t.Fatalf("second download: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("server requests = %d, want 1", requests)
+	}
+	if again.Remote.ETag != `"a1"` || !again.Verified {
+		t.Errorf("kept package = %+v, want the validators of the first download", again)
+	}
+
+	// 没有公布 SHA-256 的包无法确认缓存是否可用，总是重新下载
+	if _, err := DownloadAircraft(context.Background(), Source{URL: src.URL}, nil); err != nil {
+		t.Fatalf("unverified download: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("server requests = %d, want 2", requests)
+	}
+}
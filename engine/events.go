@@ -0,0 +1,179 @@
+// Package engine 是安装器中与界面无关的部分：下载、校验、解压、安装、收据和自更新。
+// 操作的进度通过 Event 报告给调用者，图形界面、命令行和测试都使用同一套事件。
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Phase 是事件所处的阶段。
+type Phase string
+
+const (
+	PhasePrepare  Phase = "prepare" // 创建下载缓存或临时目录
+	PhaseDownload Phase = "download"
+	PhaseRetry    Phase = "retry" // 下载失败，等待 Delay 后进行第 Attempt+1 次尝试
+	PhaseExtract  Phase = "extract"
+	PhaseSwap     Phase = "swap"    // 用临时目录中的新版本替换旧版本
+	PhaseVerify   Phase = "verify"  // 按收据或文件头校验
+	PhaseReplace  Phase = "replace" // 自更新：替换正在运行的程序
+	PhaseLaunch   Phase = "launch"  // 自更新：启动新版本并等待它报告成功
+	PhaseError    Phase = "error"
+	PhaseCancel   Phase = "cancel" // 操作被取消，临时文件已经删除
+	// PhaseUnverified 是下载完成但发布者没有公布 SHA-256，文件内容无法校验
+	PhaseUnverified Phase = "unverified"
+	// PhaseConcurrency 是自适应并发把同时安装的任务数调整为 Workers，不属于某个任务
+	PhaseConcurrency Phase = "concurrency"
+	// PhaseWarning 是操作本身成功，但保存收据、清理旧文件之类的附带步骤失败，原因在 Err 中
+	PhaseWarning Phase = "warning"
+)
+
+// Event 是操作过程中的一个进度事件，只有与 Phase 相关的字段有值。
+type Event struct {
+	Phase Phase  `json:"phase"`
+	Item  string `json:"item,omitempty"` // 正在处理的包，例如 "aircraft" 或涂装 ID
+	Path  string `json:"path,omitempty"` // 正在写入的目录或文件
+
+	// 下载：TotalBytes <= 0 表示服务器未告知长度；Speed 单位为 MB/s，仅统计本次会话下载的字节
+	Bytes       int64   `json:"bytes,omitempty"`
+	TotalBytes  int64   `json:"total_bytes,omitempty"`
+	ResumedFrom int64   `json:"resumed_from,omitempty"`
+	Speed       float64 `json:"speed,omitempty"`
+
+	// 解压和校验
+	Files      int    `json:"files,omitempty"`
+	TotalFiles int    `json:"total_files,omitempty"`
+	File       string `json:"file,omitempty"`
+
+	// 重试
+	Attempt     int           `json:"attempt,omitempty"`
+	MaxAttempts int           `json:"max_attempts,omitempty"`
+	Delay       time.Duration `json:"-"`
+
+	// 自适应并发
+	Workers int `json:"workers,omitempty"`
+
+	Err error `json:"-"`
+}
+
+// Fraction 返回 0 到 1 之间的完成比例，无法计算时返回 -1。
+func (e Event) Fraction() float64 {
+	switch {
+	case e.TotalBytes > 0:
+		return float64(e.Bytes) / float64(e.TotalBytes)
+	case e.TotalFiles > 0:
+		return float64(e.Files) / float64(e.TotalFiles)
+	}
+	return -1
+}
+
+// MarshalJSON 把 Delay 输出为秒数，Err 输出为文字。
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	out := struct {
+		event
+		DelaySeconds float64 `json:"delay_seconds,omitempty"`
+		Error        string  `json:"error,omitempty"`
+	}{event: event(e), DelaySeconds: e.Delay.Seconds()}
+	if e.Err != nil {
+		out.Error = e.Err.Error()
+	}
+	return json.Marshal(out)
+}
+
+// Sink 接收事件。事件在执行操作的 goroutine 中同步发送，Sink 不应长时间阻塞。
+type Sink func(Event)
+
+func (s Sink) emit(e Event) {
+	if s != nil {
+		s(e)
+	}
+}
+
+// forItem 返回给每个事件填上 Item 的 Sink。
+func (s Sink) forItem(item string) Sink {
+	if s == nil {
+		return nil
+	}
+	return func(e Event) {
+		if e.Item == "" {
+			e.Item = item
+		}
+		s(e)
+	}
+}
+
+// throttle 限制同一阶段的进度事件频率；阶段变化、完成、错误、警告和取消事件总是发送。
+func (s Sink) throttle(interval time.Duration) Sink {
+	if s == nil {
+		return nil
+	}
+	var mu sync.Mutex
+	var last time.Time
+	var lastPhase Phase
+	return func(e Event) {
+		mu.Lock()
+		done := e.TotalBytes > 0 && e.Bytes == e.TotalBytes || e.TotalFiles > 0 && e.Files == e.TotalFiles
+		send := e.Phase != lastPhase || done || e.Phase == PhaseError || e.Phase == PhaseWarning || e.Phase == PhaseCancel || time.Since(last) >= interval
+		if send {
+			last, lastPhase = time.Now(), e.Phase
+		}
+		mu.Unlock()
+		if send {
+			s(e)
+		}
+	}
+}
+
+// OpError 表示操作在某个阶段失败，调用者据此选择要显示的说明。
+type OpError struct {
+	Phase Phase
+	Err   error
+}
+
+func (e *OpError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// fail 发送错误事件（被取消时发送 PhaseCancel）并返回带阶段的错误。
+func fail(sink Sink, phase Phase, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		sink.emit(Event{Phase: PhaseCancel, Err: err})
+	} else {
+		sink.emit(Event{Phase: PhaseError, Err: err})
+	}
+	return &OpError{Phase: phase, Err: err}
+}
+
+// contextReader 在 ctx 取消后让读取失败，解压大文件时也能及时停止。
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// DataPath 返回程序目录下的文件路径，配置、收据和下载缓存都保存在程序旁边。
+func DataPath(filename string) (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(exePath), filename), nil
+}
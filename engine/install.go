@@ -0,0 +1,283 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	AircraftFolderName  = "AeroGennis Airbus A330-300"
+	SwapJournalFileName = "aircraft_install.json"
+	// installWorkDirName 是 X-Plane 根目录下放临时目录和旧版本备份的目录。它不在 X-Plane 扫描的 Aircraft 目录中，
+	// 安装中断时不会在飞机列表里多出一架飞机；又与飞机目录在同一个卷上，替换时只需改名。
+	installWorkDirName = ".aerogennis-installer"
+)
+
+// 安装日志的阶段。程序在替换过程中崩溃时，下次启动根据阶段恢复。
+const (
+	swapPhaseStaging  = "staging"  // 正在解压到临时目录，旧版本未被改动
+	swapPhaseSwapping = "swapping" // 正在用新版本替换旧版本
+	swapPhaseCarrying = "carrying" // 新版本已就位，正在迁移用户自行安装的涂装
+)
+
+// swapJournal 记录一次飞机安装的进度，保存在程序目录下。
+type swapJournal struct {
+	Phase   string `json:"phase"`
+	Target  string `json:"target"`
+	Staging string `json:"staging"`
+	Backup  string `json:"backup"`
+}
+
+// AircraftInstallPaths 返回飞机在 xpPath 中的安装位置，以及安装时使用的临时目录和旧版本备份目录。
+func AircraftInstallPaths(xpPath string) (target, staging, backup string) {
+	target = filepath.Join(xpPath, "Aircraft", "Laminar Research", AircraftFolderName)
+	workDir := filepath.Join(xpPath, installWorkDirName)
+	return target, filepath.Join(workDir, "staging"), filepath.Join(workDir, "backup")
+}
+
+// removeInstallWorkDir 删除 path 所在的、已经空了的安装工作目录。旧版本把临时目录放在飞机目录旁边，
+// 那时的安装日志中的路径不在工作目录中，不会删除它们的上级目录。
+func removeInstallWorkDir(path string) {
+	if dir := filepath.Dir(path); filepath.Base(dir) == installWorkDirName {
+		os.Remove(dir)
+	}
+}
+
+func swapJournalPath() (string, error) {
+	return DataPath(SwapJournalFileName)
+}
+
+func writeSwapJournal(j *swapJournal) error {
+	path, err := swapJournalPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	return AtomicWriteFile(path, data, 0644)
+}
+
+func readSwapJournal() (*swapJournal, error) {
+	path, err := swapJournalPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var j swapJournal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+func removeSwapJournal() {
+	if path, err := swapJournalPath(); err == nil {
+		os.Remove(path)
+	}
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// beginStagedInstall 清理上次残留的临时目录并记录安装开始。
+func beginStagedInstall(target, staging, backup string) error {
+	if err := os.RemoveAll(staging); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(staging), 0755); err != nil {
+		return err
+	}
+	return writeSwapJournal(&swapJournal{Phase: swapPhaseStaging, Target: target, Staging: staging, Backup: backup})
+}
+
+// abortStagedInstall 在解压或校验失败时删除临时目录，旧版本保持原样。
+func abortStagedInstall(staging string) {
+	os.RemoveAll(staging)
+	removeInstallWorkDir(staging)
+	removeSwapJournal()
+}
+
+// VerifyAircraftDir 检查目录中是否有可以被 X-Plane 加载的 .acf 文件。
+func VerifyAircraftDir(dir string) error {
+	found := false
+	rootDepth := strings.Count(filepath.Clean(dir), string(os.PathSeparator))
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && strings.Count(path, string(os.PathSeparator))-rootDepth > 1 {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(d.Name()), ".acf") {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("在 %s 中没有找到 .acf 文件", dir)
+	}
+	return nil
+}
+
+// commitStagedInstall 用 staging 目录替换 target：旧版本先改名为 backup，
+// 新版本就位并通过校验后才删除 backup；任何一步失败都恢复旧版本。
+// 新版本已就位但迁移涂装或删除 backup 失败时只发送 PhaseWarning 事件，下次启动时继续清理。
+func commitStagedInstall(target, staging, backup string, sink Sink) error {
+	if err := VerifyAircraftDir(staging); err != nil {
+		abortStagedInstall(staging)
+		return err
+	}
+	if err := os.RemoveAll(backup); err != nil {
+		abortStagedInstall(staging)
+		return err
+	}
+	journal := &swapJournal{Phase: swapPhaseSwapping, Target: target, Staging: staging, Backup: backup}
+	if err := writeSwapJournal(journal); err != nil {
+		abortStagedInstall(staging)
+		return err
+	}
+
+	hadPrevious := pathExists(target)
+	if hadPrevious {
+		if err := os.Rename(target, backup); err != nil {
+			abortStagedInstall(staging)
+			return fmt.Errorf("无法移动旧版本: %w", err)
+		}
+	}
+	if err := os.Rename(staging, target); err != nil {
+		return rollbackStagedInstall(journal, fmt.Errorf("无法移动新版本: %w", err))
+	}
+	if err := VerifyAircraftDir(target); err != nil {
+		return rollbackStagedInstall(journal, err)
+	}
+	if !hadPrevious {
+		removeInstallWorkDir(staging)
+		removeSwapJournal()
+		return nil
+	}
+
+	journal.Phase = swapPhaseCarrying
+	if err := writeSwapJournal(journal); err != nil {
+		return nil // 新版本已就位，下次启动时会继续清理
+	}
+	if err := finishStagedInstall(journal); err != nil {
+		sink.emit(Event{Phase: PhaseWarning, Path: target, Err: err})
+	}
+	return nil
+}
+
+// rollbackStagedInstall 把新版本移走并恢复 backup。
+func rollbackStagedInstall(j *swapJournal, cause error) error {
+	if pathExists(j.Target) {
+		os.RemoveAll(j.Staging)
+		if err := os.Rename(j.Target, j.Staging); err != nil {
+			return fmt.Errorf("%w; 恢复旧版本失败: %v", cause, err)
+		}
+	}
+	if pathExists(j.Backup) {
+		if err := os.Rename(j.Backup, j.Target); err != nil {
+			return fmt.Errorf("%w; 恢复旧版本失败: %v", cause, err)
+		}
+	}
+	os.RemoveAll(j.Staging)
+	removeInstallWorkDir(j.Staging)
+	removeSwapJournal()
+	return &RollbackError{cause: cause}
+}
+
+// RollbackError 表示安装失败且旧版本已经恢复。
+type RollbackError struct {
+	cause error
+}
+
+func (e *RollbackError) Error() string {
+	return fmt.Sprintf("%v (已恢复旧版本)", e.cause)
+}
+
+func (e *RollbackError) Unwrap() error {
+	return e.cause
+}
+
+// finishStagedInstall 把用户在旧版本中自行安装的涂装移到新版本，然后删除 backup。
+// 迁移失败时保留 backup 和安装日志，下次启动再试。
+func finishStagedInstall(j *swapJournal) error {
+	oldLiveries := filepath.Join(j.Backup, "liveries")
+	newLiveries := filepath.Join(j.Target, "liveries")
+	entries, err := os.ReadDir(oldLiveries)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("读取旧版本涂装目录失败: %w", err)
+	}
+	if len(entries) > 0 {
+		if err := os.MkdirAll(newLiveries, 0755); err != nil {
+			return fmt.Errorf("创建涂装目录失败: %w", err)
+		}
+	}
+	for _, entry := range entries {
+		dest := filepath.Join(newLiveries, entry.Name())
+		if pathExists(dest) {
+			continue // 新版本自带同名涂装时以新版本为准
+		}
+		if err := os.Rename(filepath.Join(oldLiveries, entry.Name()), dest); err != nil {
+			return fmt.Errorf("迁移涂装 '%s' 失败: %w", entry.Name(), err)
+		}
+	}
+	if err := os.RemoveAll(j.Backup); err != nil {
+		return fmt.Errorf("删除旧版本备份失败: %w", err)
+	}
+	removeInstallWorkDir(j.Backup)
+	removeSwapJournal()
+	return nil
+}
+
+// RecoverStagedInstall 在启动时检查上次安装是否中断，并把飞机目录恢复到一致的状态。
+// 新版本已就位但仍无法完成清理时发送 PhaseWarning 事件。
+func RecoverStagedInstall(sink Sink) {
+	sink = sink.forItem(AircraftPackageID)
+	j, err := readSwapJournal()
+	if err != nil {
+		return
+	}
+	switch j.Phase {
+	case swapPhaseStaging:
+		abortStagedInstall(j.Staging)
+	case swapPhaseSwapping:
+		switch {
+		case !pathExists(j.Target) && pathExists(j.Backup):
+			// 旧版本已移走但新版本没有就位
+			rollbackStagedInstall(j, errors.New("安装被中断"))
+		case pathExists(j.Staging):
+			// 旧版本还没有被移动
+			abortStagedInstall(j.Staging)
+		case VerifyAircraftDir(j.Target) != nil:
+			rollbackStagedInstall(j, errors.New("安装被中断"))
+		case pathExists(j.Backup):
+			if err := finishStagedInstall(j); err != nil {
+				sink.emit(Event{Phase: PhaseWarning, Path: j.Target, Err: err})
+			}
+		default:
+			removeSwapJournal()
+		}
+	case swapPhaseCarrying:
+		if err := finishStagedInstall(j); err != nil {
+			sink.emit(Event{Phase: PhaseWarning, Path: j.Target, Err: err})
+		}
+	default:
+		removeSwapJournal()
+	}
+}
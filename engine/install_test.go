@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeAircraft 在 dir 中放一个 .acf 文件和一个内容为 version 的版本文件。
+func writeAircraft(t *testing.T, dir, version string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"A330.acf": "acf", "version.txt": version} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestStagedInstallOutsideAircraftFolder 检查临时目录和旧版本备份不在 X-Plane 扫描的 Aircraft 目录中，
+// 替换完成后工作目录被删除。
+func TestStagedInstallOutsideAircraftFolder(t *testing.T) {
+	xpPath := t.TempDir()
+	target, staging, backup := AircraftInstallPaths(xpPath)
+	aircraftDir := filepath.Join(xpPath, "Aircraft")
+	for _, dir := range []string{staging, backup} {
+		if strings.HasPrefix(dir, aircraftDir+string(os.PathSeparator)) {
+			t.Errorf("%s is inside the Aircraft folder", dir)
+		}
+	}
+
+	writeAircraft(t, target, "1.0")
+	if err := beginStagedInstall(target, staging, backup); err != nil {
+		t.Fatal(err)
+	}
+	writeAircraft(t, staging, "2.0")
+	if err := commitStagedInstall(target, staging, backup, nil); err != nil {
+		t.Fatalf("commitStagedInstall: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(target, "version.txt")); err != nil || string(data) != "2.0" {
+		t.Errorf("installed version = %q, %v; want 2.0", data, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(target)); len(entries) != 1 {
+		t.Errorf("Laminar Research folder has %d entries, want only the aircraft", len(entries))
+	}
+	if _, err := os.Stat(filepath.Join(xpPath, installWorkDirName)); !os.IsNotExist(err) {
+		t.Errorf("install work folder not removed: %v", err)
+	}
+}
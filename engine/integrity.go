@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Source 描述一个可下载的包及发布者给出的 SHA-256 和字节数。
+// SHA256 为空或 Size 为 0 时不做对应的检查；没有 SHA256 时 Download 发送 PhaseUnverified 事件。
+type Source struct {
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+}
+
+// IntegrityError 表示下载的文件与发布者公布的哈希或大小不一致。
+type IntegrityError struct {
+	URL            string
+	ExpectedSHA256 string
+	ActualSHA256   string
+	ExpectedSize   int64
+	ActualSize     int64
+}
+
+func (e *IntegrityError) Error() string {
+	if e.ExpectedSHA256 != "" && e.ActualSHA256 != "" {
+		return fmt.Sprintf("SHA-256 不匹配: 期望 %s, 实际 %s", e.ExpectedSHA256, e.ActualSHA256)
+	}
+	return fmt.Sprintf("文件大小不匹配: 期望 %d 字节, 实际 %d 字节", e.ExpectedSize, e.ActualSize)
+}
+
+func NormalizeSHA256(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// checkSize 在下载开始前用服务器报告的长度做一次快速检查，避免下载一个注定不匹配的大文件。
+func (src Source) checkSize(actual int64) error {
+	if src.Size > 0 && actual > 0 && actual != src.Size {
+		return &IntegrityError{URL: src.URL, ExpectedSize: src.Size, ActualSize: actual}
+	}
+	return nil
+}
+
+// verify 比较下载完成后的大小和哈希。
+func (src Source) verify(actualSize int64, actualSHA256 string) error {
+	if err := src.checkSize(actualSize); err != nil {
+		return err
+	}
+	if expected := NormalizeSHA256(src.SHA256); expected != "" && expected != actualSHA256 {
+		return &IntegrityError{URL: src.URL, ExpectedSHA256: expected, ActualSHA256: actualSHA256}
+	}
+	return nil
+}
+
+// hashFile 计算已存在文件的 SHA-256，用于续传时补齐之前下载部分的哈希。
+func hashFile(h hash.Hash, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(h, f)
+	return err
+}
+
+// FileSHA256 返回文件的十六进制 SHA-256。
+func FileSHA256(path string) (string, error) {
+	h := sha256.New()
+	if err := hashFile(h, path); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ParseSourceFragment 从旧格式涂装列表的链接中取出 "#sha256=...&size=..." 片段。
+// 片段不会发送给服务器，旧版本程序仍可正常下载。
+func ParseSourceFragment(rawURL string) Source {
+	base, fragment, found := strings.Cut(rawURL, "#")
+	src := Source{URL: base}
+	if !found {
+		return src
+	}
+	values, err := url.ParseQuery(fragment)
+	if err != nil {
+		return Source{URL: rawURL}
+	}
+	src.SHA256 = NormalizeSHA256(values.Get("sha256"))
+	if size, err := strconv.ParseInt(values.Get("size"), 10, 64); err == nil {
+		src.Size = size
+	}
+	return src
+}
@@ -0,0 +1,323 @@
+package engine
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Extract 把压缩包解压到 destRoot，返回写入的文件用于安装收据。
+// 开始时发送一个 File 为空、Path 为 destRoot 的 PhaseExtract 事件，之后每 50ms 最多发送一次进度。
+// ctx 被取消时停止并返回 ctx.Err()，已写入的文件由调用者连同临时目录一起删除。
+func Extract(ctx context.Context, zipFile, destRoot string, sink Sink) ([]ReceiptFile, error) {
+	r, err := zip.OpenReader(zipFile)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	totalFiles := len(r.File)
+	sink.emit(Event{Phase: PhaseExtract, Path: destRoot, TotalFiles: totalFiles})
+	progress := sink.throttle(50 * time.Millisecond)
+	var files []ReceiptFile
+	for i, f := range r.File {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		fpath := filepath.Join(destRoot, f.Name)
+		// 路径安全验证
+		if !strings.HasPrefix(filepath.Clean(fpath), filepath.Clean(destRoot)+string(os.PathSeparator)) {
+			return nil, fmt.Errorf("非法文件路径: %s", fpath)
+		}
+		progress.emit(Event{Phase: PhaseExtract, Path: destRoot, File: f.Name, Files: i + 1, TotalFiles: totalFiles})
+
+		if f.FileInfo().IsDir() {
+			os.MkdirAll(fpath, os.ModePerm)
+			continue
+		}
+		file, err := extractZipFile(ctx, f, fpath)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// AircraftPackage 是已下载到缓存、可以安装到多个 X-Plane 的飞机包。
+type AircraftPackage struct {
+	Source   Source
+	ZipPath  string
+	SHA256   string
+	Verified bool              // 已按发布者公布的 SHA-256 校验过
+	Remote   RemotePackageInfo // 下载时服务器上的 ETag 和 Last-Modified，记录在收据中用于判断更新
+}
+
+// DownloadAircraft 把飞机包下载到程序目录下的缓存，中断后重新安装可以继续下载；被取消时删除未完成的下载。
+// 上次下载完但没有 Remove 的包与 src 公布的 SHA-256 一致时直接使用，不再下载。
+func DownloadAircraft(ctx context.Context, src Source, sink Sink) (*AircraftPackage, error) {
+	sink = sink.forItem(AircraftPackageID)
+	sink.emit(Event{Phase: PhasePrepare})
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return nil, fail(sink, PhasePrepare, err)
+	}
+	zipPath := filepath.Join(cacheDir, "aircraft.zip")
+	verified := NormalizeSHA256(src.SHA256) != ""
+	if remote, ok := keptDownload(src, zipPath); ok {
+		sink.emit(Event{Phase: PhaseDownload, Path: zipPath, Bytes: remote.Size, TotalBytes: remote.Size})
+		return &AircraftPackage{Source: src, ZipPath: zipPath, SHA256: PackageSHA256(src, zipPath), Verified: verified, Remote: remote}, nil
+	}
+	remote, err := Download(ctx, src, zipPath, sink)
+	if err != nil {
+		return nil, fail(sink, PhaseDownload, err)
+	}
+	keepDownload(src, zipPath, remote) // 记录失败只是下次需要重新下载
+	return &AircraftPackage{Source: src, ZipPath: zipPath, SHA256: PackageSHA256(src, zipPath), Verified: verified, Remote: remote}, nil
+}
+
+// Remove 删除缓存中的飞机包。安装失败时调用者应保留它，重试时不必重新下载。
+func (p *AircraftPackage) Remove() {
+	os.Remove(p.ZipPath)
+	os.Remove(p.ZipPath + ".json")
+}
+
+// InstallAircraft 把飞机包安装到 xpPath 并记录收据：先解压到 X-Plane 根目录下、Aircraft 目录之外的临时目录，
+// 校验通过后再替换旧版本，失败或被取消时删除临时目录，旧版本不受影响。返回安装位置和包内的版本号。
+// 替换一旦开始就不再响应取消，以免留下不完整的飞机目录。
+func InstallAircraft(ctx context.Context, pkg *AircraftPackage, xpPath string, sink Sink) (target, version string, err error) {
+	sink = sink.forItem(AircraftPackageID)
+	target, staging, backup := AircraftInstallPaths(xpPath)
+	if err := beginStagedInstall(target, staging, backup); err != nil {
+		return "", "", fail(sink, PhasePrepare, err)
+	}
+	files, err := Extract(ctx, pkg.ZipPath, staging, sink)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		abortStagedInstall(staging)
+		return "", "", fail(sink, PhaseExtract, err)
+	}
+
+	version = ReadVersionMarker(staging)
+	sink.emit(Event{Phase: PhaseSwap, Path: target})
+	if err := commitStagedInstall(target, staging, backup, sink); err != nil {
+		return "", "", fail(sink, PhaseSwap, err)
+	}
+	err = RecordReceipt(Receipt{
+		PackageID:    AircraftPackageID,
+		Name:         AircraftFolderName,
+		Version:      version,
+		SourceURL:    pkg.Source.URL,
+		ETag:         pkg.Remote.ETag,
+		LastModified: pkg.Remote.LastModified,
+		SHA256:       pkg.SHA256,
+		Root:         target,
+		InstalledAt:  time.Now(),
+		Files:        files,
+	})
+	if err != nil {
+		sink.emit(Event{Phase: PhaseWarning, Err: fmt.Errorf("保存安装记录失败: %w", err)})
+	}
+	return target, version, nil
+}
+
+// LiveryPackage 是涂装目录中的一个可安装涂装。
+type LiveryPackage struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"` // 目录中的版本，记入收据，用于判断已安装的涂装是否过期
+	URL     string `json:"url"`               // 目录中的原始链接，用作缓存文件名和收据中的来源
+	Source  Source `json:"source"`
+}
+
+// InstallLivery 下载一个涂装并解压到 liveryDirs 中的每个目录。每个目录都先解压到旁边的临时目录，
+// 完整解压后才移入涂装目录，被取消或失败时不会留下解压了一半的涂装文件夹。
+// 下载失败或因 ErrPaused 取消时保留 .part 文件，下次安装同一涂装时续传；其它取消连同 .part 一起删除。
+// 更新已安装的涂装时，旧版本有而新版本没有的文件会被删除。
+func InstallLivery(ctx context.Context, l LiveryPackage, liveryDirs []string, sink Sink) error {
+	sink = sink.forItem(l.ID)
+	sink.emit(Event{Phase: PhasePrepare})
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return fail(sink, PhasePrepare, fmt.Errorf("创建下载缓存目录失败: %w", err))
+	}
+	zipPath := LiveryCachePath(cacheDir, l.URL)
+	if _, err := Download(ctx, l.Source, zipPath, sink); err != nil {
+		return fail(sink, PhaseDownload, err)
+	}
+	defer os.Remove(zipPath)
+
+	packageHash := PackageSHA256(l.Source, zipPath)
+	var failures []error
+	for _, liveryDir := range liveryDirs {
+		if err := ctx.Err(); err != nil {
+			return fail(sink, PhaseExtract, err)
+		}
+		files, err := extractLivery(ctx, zipPath, liveryDir, l.ID, sink)
+		if ctx.Err() != nil {
+			return fail(sink, PhaseExtract, ctx.Err())
+		}
+		if err != nil {
+			failures = append(failures, fmt.Errorf("解压到 %s 失败: %w", liveryDir, err))
+			continue
+		}
+		staleErr, err := replaceReceipt(Receipt{
+			PackageID:   LiveryPackageID(l.ID),
+			Name:        l.Name,
+			Version:     l.Version,
+			SourceURL:   l.URL,
+			SHA256:      packageHash,
+			Root:        liveryDir,
+			InstalledAt: time.Now(),
+			Files:       files,
+		})
+		if staleErr != nil {
+			sink.emit(Event{Phase: PhaseWarning, Path: liveryDir, Err: fmt.Errorf("删除旧版本文件失败: %w", staleErr)})
+		}
+		if err != nil {
+			sink.emit(Event{Phase: PhaseWarning, Path: liveryDir, Err: fmt.Errorf("保存 '%s' 的安装记录失败: %w", l.Name, err)})
+		}
+	}
+	if len(failures) > 0 {
+		return fail(sink, PhaseExtract, errors.Join(failures...))
+	}
+	return nil
+}
+
+// liveryStagingDir 返回涂装解压用的临时目录，与涂装目录在同一个磁盘上以便直接改名。
+func liveryStagingDir(liveryDir, id string) string {
+	return fmt.Sprintf("%s.staging-%x", liveryDir, sha1.Sum([]byte(id)))
+}
+
+// extractLivery 把涂装包解压到临时目录，再把其中的文件移入 liveryDir。
+func extractLivery(ctx context.Context, zipPath, liveryDir, id string, sink Sink) ([]ReceiptFile, error) {
+	staging := liveryStagingDir(liveryDir, id)
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+	files, err := Extract(ctx, zipPath, staging, sink)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(liveryDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := mergeDir(staging, liveryDir); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// mergeDir 把 src 中的内容移入 dst：dst 中没有的目录整个改名过去，已有的目录逐个移动文件，同名文件被替换。
+// 用户在已有涂装文件夹中自行添加的文件因此得以保留。
+func mergeDir(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		from, to := filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())
+		info, err := os.Stat(to)
+		switch {
+		case err != nil:
+			if err := os.Rename(from, to); err != nil {
+				return err
+			}
+		case entry.IsDir() && info.IsDir():
+			if err := mergeDir(from, to); err != nil {
+				return err
+			}
+		default:
+			if err := os.RemoveAll(to); err != nil {
+				return err
+			}
+			if err := os.Rename(from, to); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// InstalledLiveryDirs 列出飞机涂装目录下的文件夹。
+func InstalledLiveryDirs(liveriesPath string) ([]string, error) {
+	entries, err := os.ReadDir(liveriesPath)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// UninstallLiveryDirs 删除涂装文件夹：有安装记录的涂装只删除记录中属于这个文件夹的文件，
+// 同一个包安装的其它文件夹不受影响；没有记录的（手动安装的）删除整个文件夹。
+func UninstallLiveryDirs(liveriesPath string, names []string) (deletedCount int, errorMessages []string) {
+	receiptByDir := make(map[string]Receipt)
+	if db, err := LoadReceipts(); err == nil {
+		for _, r := range db.ForRoot(liveriesPath) {
+			for _, dir := range r.TopLevelDirs() {
+				receiptByDir[dir] = r
+			}
+		}
+	}
+	for _, name := range names {
+		pathToDelete := filepath.Join(liveriesPath, name)
+		if r, ok := receiptByDir[name]; ok {
+			if err := UninstallReceiptDir(r, name); err != nil {
+				errorMessages = append(errorMessages, fmt.Sprintf("%s: %v", name, err))
+			} else {
+				deletedCount++
+			}
+			continue
+		}
+		if err := os.RemoveAll(pathToDelete); err != nil {
+			errorMessages = append(errorMessages, fmt.Sprintf("%s: %v", name, err))
+		} else {
+			deletedCount++
+		}
+	}
+	return deletedCount, errorMessages
+}
+
+// UninstallAircraft 删除飞机目录及安装在其中的所有收据。目录已删除时收据保存失败不算失败，过期的收据不会被用到。
+func UninstallAircraft(aircraftPath string) error {
+	if err := os.RemoveAll(aircraftPath); err != nil {
+		return err
+	}
+	UpdateReceipts(func(db *ReceiptDB) { db.RemoveUnder(aircraftPath) })
+	return nil
+}
+
+// VerifyAircraft 按安装收据校验 aircraftPath 中的飞机文件；没有收据时返回 nil 收据。
+func VerifyAircraft(ctx context.Context, aircraftPath string, sink Sink) (*Receipt, []ReceiptProblem, error) {
+	db, err := LoadReceipts()
+	if err != nil {
+		return nil, nil, err
+	}
+	receipt := db.Find(AircraftPackageID, aircraftPath)
+	if receipt == nil {
+		return nil, nil, nil
+	}
+	problems, err := VerifyReceipt(ctx, *receipt, sink.forItem(AircraftPackageID).throttle(50*time.Millisecond))
+	if err != nil {
+		return nil, nil, err
+	}
+	return receipt, problems, nil
+}
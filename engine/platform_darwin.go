@@ -0,0 +1,26 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// X-Plane 12 在 macOS 上是一个应用程序包。
+var xplaneExecutables = []string{"X-Plane.app"}
+
+// 64 位 Mach-O 与通用二进制文件的文件头。
+var executableMagics = [][]byte{{0xcf, 0xfa, 0xed, 0xfe}, {0xca, 0xfe, 0xba, 0xbe}}
+
+func makeExecutable(path string) error {
+	return os.Chmod(path, 0755)
+}
+
+// xplaneInstallRecordFiles 返回 X-Plane 安装程序记录安装位置的文件。
+func xplaneInstallRecordFiles(home string) []string {
+	return []string{filepath.Join(home, "Library", "Preferences", "x-plane_install_12.txt")}
+}
+
+// steamLibraryFolderFiles 返回 Steam 记录游戏库位置的文件。
+func steamLibraryFolderFiles(home string) []string {
+	return []string{filepath.Join(home, "Library", "Application Support", "Steam", "steamapps", "libraryfolders.vdf")}
+}
@@ -0,0 +1,31 @@
+//go:build !windows && !darwin
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// X-Plane 12 在 Linux 上的主程序。
+var xplaneExecutables = []string{"X-Plane-x86_64"}
+
+var executableMagics = [][]byte{[]byte("\x7fELF")}
+
+func makeExecutable(path string) error {
+	return os.Chmod(path, 0755)
+}
+
+// xplaneInstallRecordFiles 返回 X-Plane 安装程序记录安装位置的文件。
+func xplaneInstallRecordFiles(home string) []string {
+	return []string{filepath.Join(home, ".x-plane", "x-plane_install_12.txt")}
+}
+
+// steamLibraryFolderFiles 返回 Steam 记录游戏库位置的文件，原生安装和 Flatpak 安装的位置不同。
+func steamLibraryFolderFiles(home string) []string {
+	return []string{
+		filepath.Join(home, ".steam", "steam", "steamapps", "libraryfolders.vdf"),
+		filepath.Join(home, ".local", "share", "Steam", "steamapps", "libraryfolders.vdf"),
+		filepath.Join(home, ".var", "app", "com.valvesoftware.Steam", ".local", "share", "Steam", "steamapps", "libraryfolders.vdf"),
+	}
+}
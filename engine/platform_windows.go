@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// X-Plane 12 在 Windows 上的主程序。
+var xplaneExecutables = []string{"X-Plane.exe"}
+
+// PE 可执行文件以 "MZ" 开头。
+var executableMagics = [][]byte{[]byte("MZ")}
+
+// makeExecutable 在 Windows 上无需设置权限。
+func makeExecutable(path string) error {
+	return nil
+}
+
+// xplaneInstallRecordFiles 返回 X-Plane 安装程序记录安装位置的文件。
+func xplaneInstallRecordFiles(home string) []string {
+	local := os.Getenv("LOCALAPPDATA")
+	if local == "" {
+		local = filepath.Join(home, "AppData", "Local")
+	}
+	return []string{filepath.Join(local, "x-plane_install_12.txt")}
+}
+
+// steamLibraryFolderFiles 返回 Steam 记录游戏库位置的文件。
+func steamLibraryFolderFiles(home string) []string {
+	var files []string
+	for _, env := range []string{"ProgramFiles(x86)", "ProgramFiles"} {
+		if dir := os.Getenv(env); dir != "" {
+			files = append(files, filepath.Join(dir, "Steam", "steamapps", "libraryfolders.vdf"))
+		}
+	}
+	return files
+}
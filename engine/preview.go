@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// PreviewCacheDirName 是程序目录下保存涂装预览图的目录。
+	PreviewCacheDirName = "previews"
+	// DefaultPreviewCacheSize 和 DefaultPreviewMaxAge 是预览图缓存的默认大小上限和有效期。
+	DefaultPreviewCacheSize = 64 << 20
+	DefaultPreviewMaxAge    = 30 * 24 * time.Hour
+	// maxPreviewSize 是单张预览图的大小上限，超过的不下载。
+	maxPreviewSize = 8 << 20
+)
+
+// ErrPreviewTooLarge 表示预览图超过 maxPreviewSize。
+var ErrPreviewTooLarge = errors.New("预览图过大")
+
+// PreviewCache 是涂装预览图的磁盘缓存。每张图按链接保存为一个文件，
+// 下载超过 MaxAge 的重新下载；缓存合计超过 MaxBytes 时从最早下载的开始删除。
+type PreviewCache struct {
+	Dir      string
+	MaxBytes int64
+	MaxAge   time.Duration
+	mu       sync.Mutex // 保护清理，多张图同时下载完成时只有一个在删文件
+}
+
+// NewPreviewCache 打开程序目录下的预览图缓存，并删除过期和超出大小上限的文件。
+func NewPreviewCache() (*PreviewCache, error) {
+	dir, err := DataPath(PreviewCacheDirName)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	c := &PreviewCache{Dir: dir, MaxBytes: DefaultPreviewCacheSize, MaxAge: DefaultPreviewMaxAge}
+	c.prune()
+	return c, nil
+}
+
+// path 返回 rawURL 对应的缓存文件，保留链接中的图片扩展名。
+func (c *PreviewCache) path(rawURL string) string {
+	name := fmt.Sprintf("%x", sha256.Sum256([]byte(rawURL)))[:32]
+	if u, err := url.Parse(rawURL); err == nil {
+		switch ext := strings.ToLower(path.Ext(u.Path)); ext {
+		case ".png", ".jpg", ".jpeg":
+			name += ext
+		}
+	}
+	return filepath.Join(c.Dir, name)
+}
+
+// Get 返回 rawURL 对应的本地预览图，缓存中没有或已过期时下载。
+// 预览图与涂装下载共用 DownloadLimiter 的速度上限，失败时不重试。
+func (c *PreviewCache) Get(ctx context.Context, rawURL string) (string, error) {
+	p := c.path(rawURL)
+	if info, err := os.Stat(p); err == nil && time.Since(info.ModTime()) < c.MaxAge {
+		return p, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", newHTTPStatusError(resp)
+	}
+	if resp.ContentLength > maxPreviewSize {
+		return "", ErrPreviewTooLarge
+	}
+	data, err := io.ReadAll(io.LimitReader(limitedReader{ctx: ctx, r: resp.Body, limiter: DownloadLimiter}, maxPreviewSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxPreviewSize {
+		return "", ErrPreviewTooLarge
+	}
+	if err := AtomicWriteFile(p, data, 0644); err != nil {
+		return "", err
+	}
+	c.prune()
+	return p, nil
+}
+
+// prune 删除过期的预览图，再从最早下载的开始删除，直到缓存不超过 MaxBytes。
+func (c *PreviewCache) prune() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return
+	}
+	type cached struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []cached
+	var total int64
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() || strings.HasSuffix(e.Name(), ".tmp") {
+			continue
+		}
+		files = append(files, cached{filepath.Join(c.Dir, e.Name()), info.Size(), info.ModTime()})
+		total += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= c.MaxBytes && time.Since(f.modTime) < c.MaxAge {
+			continue
+		}
+		if err := os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+}
+
+// LocalPreview 返回已安装涂装自带的 X-Plane 图标（*icon11.png），没有时返回空字符串。
+// 有多个时使用目录层级最浅的一个。
+func LocalPreview(r *Receipt) string {
+	best := ""
+	for _, f := range r.Files {
+		if !strings.HasSuffix(strings.ToLower(f.Path), "icon11.png") {
+			continue
+		}
+		if best == "" || strings.Count(f.Path, "/") < strings.Count(best, "/") {
+			best = f.Path
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	p := filepath.Join(r.Root, filepath.FromSlash(best))
+	if _, err := os.Stat(p); err != nil {
+		return ""
+	}
+	return p
+}
@@ -0,0 +1,355 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	QueueFileName      = "queue.json"
+	queueSchemaVersion = 1
+)
+
+// ErrPaused 是暂停正在安装的任务时取消它所用的原因，下载保留 .part，继续时从断点续传。
+var ErrPaused = errors.New("已暂停")
+
+// errJobRemoved 是从队列中删除正在安装的任务时取消它所用的原因。
+var errJobRemoved = errors.New("已从队列中删除")
+
+// JobState 是队列中任务的状态。
+type JobState string
+
+const (
+	JobQueued  JobState = "queued"
+	JobRunning JobState = "running"
+	JobPaused  JobState = "paused"
+	JobDone    JobState = "done"
+	JobFailed  JobState = "failed"
+)
+
+// QueueJob 是队列中的一个涂装安装任务：下载一次，再解压到 LiveryDirs 中的每个目录。
+type QueueJob struct {
+	Livery     LiveryPackage `json:"livery"`
+	LiveryDirs []string      `json:"livery_dirs"`
+	State      JobState      `json:"state"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// ID 返回任务的涂装 ID，同一个涂装在队列中只有一个任务。
+func (j QueueJob) ID() string {
+	return j.Livery.ID
+}
+
+// LiveryQueue 是保存在 queue.json 中的涂装安装队列。运行时可以暂停、继续、调整顺序或删除单个任务，
+// 每次变化都立即保存，程序重启后可以接着安装。
+type LiveryQueue struct {
+	// OnChange 在任务状态或顺序变化并保存后调用，不持有锁，可以在其中调用 Jobs。
+	OnChange func()
+	// Events 接收不属于某次 Run 的事件，例如保存 queue.json 失败时的 PhaseWarning，不持有锁。
+	Events Sink
+
+	mu      sync.Mutex
+	jobs    []*QueueJob
+	cancels map[*QueueJob]context.CancelCauseFunc // 正在安装或暂停、删除后还未停下的任务
+}
+
+type queueFile struct {
+	SchemaVersion int         `json:"schema_version"`
+	Jobs          []*QueueJob `json:"jobs"`
+}
+
+// LoadQueue 读取 queue.json，文件不存在时返回空队列。上次退出时正在安装的任务改为等待。
+func LoadQueue() (*LiveryQueue, error) {
+	q := &LiveryQueue{cancels: make(map[*QueueJob]context.CancelCauseFunc)}
+	p, err := DataPath(QueueFileName)
+	if err != nil {
+		return q, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return q, err
+	}
+	var f queueFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return q, fmt.Errorf("无法读取 %s: %w", QueueFileName, err)
+	}
+	for _, job := range f.Jobs {
+		if job.State == JobRunning {
+			job.State = JobQueued
+		}
+	}
+	q.jobs = f.Jobs
+	return q, nil
+}
+
+// save 原子地写入 queue.json，调用者持有锁。
+func (q *LiveryQueue) save() error {
+	p, err := DataPath(QueueFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(queueFile{SchemaVersion: queueSchemaVersion, Jobs: q.jobs}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return AtomicWriteFile(p, data, 0644)
+}
+
+// update 在锁内修改队列，保存后通知 OnChange；保存失败时在释放锁后向 Events 发送 PhaseWarning。
+func (q *LiveryQueue) update(fn func() bool) {
+	q.mu.Lock()
+	changed := fn()
+	var saveErr error
+	if changed {
+		saveErr = q.save()
+	}
+	q.mu.Unlock()
+	if saveErr != nil {
+		q.Events.emit(Event{Phase: PhaseWarning, Err: fmt.Errorf("保存安装队列失败: %w", saveErr)})
+	}
+	if changed && q.OnChange != nil {
+		q.OnChange()
+	}
+}
+
+func (q *LiveryQueue) find(id string) int {
+	for i, job := range q.jobs {
+		if job.ID() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Jobs 返回队列中任务的副本，按执行顺序排列。
+func (q *LiveryQueue) Jobs() []QueueJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	jobs := make([]QueueJob, len(q.jobs))
+	for i, job := range q.jobs {
+		jobs[i] = *job
+	}
+	return jobs
+}
+
+// Add 把涂装加到队尾。已在队列中且未完成的涂装保持原样；已完成或失败的重新排队。
+func (q *LiveryQueue) Add(l LiveryPackage, liveryDirs []string) {
+	q.update(func() bool {
+		if i := q.find(l.ID); i >= 0 {
+			job := q.jobs[i]
+			if job.State != JobDone && job.State != JobFailed {
+				return false
+			}
+			q.jobs = append(q.jobs[:i], q.jobs[i+1:]...)
+		}
+		q.jobs = append(q.jobs, &QueueJob{Livery: l, LiveryDirs: liveryDirs, State: JobQueued})
+		return true
+	})
+}
+
+// Pause 暂停一个等待中或正在安装的任务；正在下载的部分保留，继续时续传。
+func (q *LiveryQueue) Pause(id string) {
+	q.update(func() bool {
+		i := q.find(id)
+		if i < 0 || (q.jobs[i].State != JobQueued && q.jobs[i].State != JobRunning) {
+			return false
+		}
+		if cancel, ok := q.cancels[q.jobs[i]]; ok {
+			cancel(ErrPaused)
+		}
+		q.jobs[i].State = JobPaused
+		return true
+	})
+}
+
+// Resume 让暂停的任务重新排队。
+func (q *LiveryQueue) Resume(id string) {
+	q.update(func() bool {
+		i := q.find(id)
+		if i < 0 || q.jobs[i].State != JobPaused {
+			return false
+		}
+		q.jobs[i].State = JobQueued
+		return true
+	})
+}
+
+// Remove 从队列中删除任务，正在安装的任务会被取消并删除未完成的下载。
+func (q *LiveryQueue) Remove(id string) {
+	q.update(func() bool {
+		i := q.find(id)
+		if i < 0 {
+			return false
+		}
+		if cancel, ok := q.cancels[q.jobs[i]]; ok {
+			cancel(errJobRemoved)
+		}
+		q.jobs = append(q.jobs[:i], q.jobs[i+1:]...)
+		return true
+	})
+}
+
+// Move 把任务在队列中前移（delta < 0）或后移，等待中的任务按队列顺序开始安装。
+func (q *LiveryQueue) Move(id string, delta int) {
+	q.update(func() bool {
+		i := q.find(id)
+		j := min(max(i+delta, 0), len(q.jobs)-1)
+		if i < 0 || i == j {
+			return false
+		}
+		job := q.jobs[i]
+		q.jobs = append(q.jobs[:i], q.jobs[i+1:]...)
+		q.jobs = append(q.jobs[:j], append([]*QueueJob{job}, q.jobs[j:]...)...)
+		return true
+	})
+}
+
+// RetryFailed 让失败的任务重新排队，返回重新排队的任务数。
+func (q *LiveryQueue) RetryFailed() int {
+	n := 0
+	q.update(func() bool {
+		for _, job := range q.jobs {
+			if job.State == JobFailed {
+				job.State, job.Error = JobQueued, ""
+				n++
+			}
+		}
+		return n > 0
+	})
+	return n
+}
+
+// ClearFinished 删除已完成和失败的任务。
+func (q *LiveryQueue) ClearFinished() {
+	q.update(func() bool {
+		kept := q.jobs[:0]
+		for _, job := range q.jobs {
+			if job.State != JobDone && job.State != JobFailed {
+				kept = append(kept, job)
+			}
+		}
+		changed := len(kept) != len(q.jobs)
+		clear(q.jobs[len(kept):])
+		q.jobs = kept
+		return changed
+	})
+}
+
+// Pending 返回等待中的任务数。
+func (q *LiveryQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, job := range q.jobs {
+		if job.State == JobQueued {
+			n++
+		}
+	}
+	return n
+}
+
+// next 取出第一个等待中的任务并标记为正在安装，没有时返回 nil。
+// 暂停后立即继续的任务要等上一次安装停下后才会再次取出。
+func (q *LiveryQueue) next(ctx context.Context) (*QueueJob, context.Context) {
+	var job *QueueJob
+	var jobCtx context.Context
+	q.update(func() bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		for _, j := range q.jobs {
+			if _, busy := q.cancels[j]; j.State == JobQueued && !busy {
+				var cancel context.CancelCauseFunc
+				jobCtx, cancel = context.WithCancelCause(ctx)
+				q.cancels[j] = cancel
+				j.State, j.Error = JobRunning, ""
+				job = j
+				return true
+			}
+		}
+		return false
+	})
+	return job, jobCtx
+}
+
+// finish 按安装结果更新任务。被暂停或删除的任务已经在 Pause、Remove 中处理；
+// 整个队列被取消时任务回到等待状态，下次运行时重新安装。
+func (q *LiveryQueue) finish(job *QueueJob, jobCtx context.Context, err error) {
+	cancelled := jobCtx.Err() != nil
+	q.update(func() bool {
+		q.cancels[job](nil)
+		delete(q.cancels, job)
+		if i := q.find(job.ID()); i < 0 || q.jobs[i] != job || job.State != JobRunning {
+			return false
+		}
+		switch {
+		case err == nil:
+			job.State = JobDone
+		case cancelled:
+			job.State = JobQueued
+		default:
+			job.State, job.Error = JobFailed, err.Error()
+		}
+		return true
+	})
+}
+
+// Run 按 c 设置的并发数安装等待中的任务，直到没有等待中的任务或 ctx 被取消。
+// 运行期间加入或继续的任务也会被安装；暂停的任务留在队列中。
+func (q *LiveryQueue) Run(ctx context.Context, c Concurrency, sink Sink) {
+	ctrl := newConcurrencyController(c)
+	sink = ctrl.observe(sink)
+	var wg sync.WaitGroup
+	worker := func() {
+		defer wg.Done()
+		for ctrl.keep() {
+			job, jobCtx := q.next(ctx)
+			if job == nil {
+				ctrl.stop()
+				return
+			}
+			err := InstallLivery(jobCtx, job.Livery, job.LiveryDirs, sink)
+			q.finish(job, jobCtx, err)
+		}
+	}
+	spawn := func(initial bool) {
+		for ctrl.start(initial, &wg) {
+			go worker()
+		}
+	}
+	spawn(true)
+	if c.Adaptive {
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			ticker := time.NewTicker(adaptInterval)
+			defer ticker.Stop()
+			last, workers := time.Now(), max(c.Workers, 1)
+			for {
+				select {
+				case <-done:
+					return
+				case now := <-ticker.C:
+					if target := ctrl.adjust(now.Sub(last)); target != workers {
+						sink.emit(Event{Phase: PhaseConcurrency, Workers: target})
+						workers = target
+					}
+					last = now
+					if q.Pending() > 0 {
+						spawn(false)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
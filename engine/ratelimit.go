@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// RateLimiter 是所有下载共用的令牌桶，限制合计的下载速度，让模拟机所在的网络在下载时仍然可用。
+// 令牌可以透支：读到一块数据后扣除对应的字节数，不够时等待补足，因此任意大小的读取都能按速度放行。
+// 速度上限可以按一天中的时段变化（例如 22:00 以后不限速），每次读取时按当前时间选用。
+type RateLimiter struct {
+	mu     sync.Mutex
+	base   float64 // 没有时段规则匹配时的速度上限
+	rules  []BandwidthRule
+	rate   float64 // 当前生效的速度上限（字节/秒），0 为不限
+	tokens float64
+	last   time.Time
+}
+
+// BandwidthRule 在每天 Start 到 End 之间使用 BytesPerSecond 作为速度上限，0 为不限。
+// Start 和 End 是从零点起的时间，End 不大于 Start 时跨过午夜到次日的 End。
+type BandwidthRule struct {
+	Start          time.Duration
+	End            time.Duration
+	BytesPerSecond int64
+}
+
+// Contains 判断 t 是否在规则的时段内。
+func (r BandwidthRule) Contains(t time.Time) bool {
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if r.End > r.Start {
+		return offset >= r.Start && offset < r.End
+	}
+	return offset >= r.Start || offset < r.End
+}
+
+// DownloadLimiter 限制 Download 的合计速度，默认不限，由配置设置。
+var DownloadLimiter = &RateLimiter{}
+
+// SetRate 设置固定的速度上限（字节/秒），0 或负数为不限，并清除时段规则。
+func (l *RateLimiter) SetRate(bytesPerSecond int64) {
+	l.SetSchedule(bytesPerSecond, nil)
+}
+
+// SetSchedule 设置默认的速度上限和按时段的规则，多条规则重叠时使用靠前的一条。
+// 正在进行的下载立即按新速度放行。
+func (l *RateLimiter) SetSchedule(bytesPerSecond int64, rules []BandwidthRule) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.base = float64(max(bytesPerSecond, 0))
+	l.rules = append([]BandwidthRule(nil), rules...)
+	l.rate = -1 // 强制 update 重新选用
+	l.update(time.Now())
+}
+
+// Rate 返回当前生效的速度上限（字节/秒），0 为不限。
+func (l *RateLimiter) Rate() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.update(time.Now())
+	return int64(l.rate)
+}
+
+// update 按 now 选用速度上限，变化时清空令牌桶。调用者持有锁。
+func (l *RateLimiter) update(now time.Time) {
+	rate := l.base
+	for _, r := range l.rules {
+		if r.Contains(now) {
+			rate = float64(max(r.BytesPerSecond, 0))
+			break
+		}
+	}
+	if rate != l.rate {
+		l.rate, l.tokens, l.last = rate, 0, now
+	}
+}
+
+// wait 扣除 n 字节的令牌，令牌不足时等待，ctx 被取消时立即返回。
+func (l *RateLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.update(now)
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	// 桶的容量为一秒的流量，空闲一段时间后不会突然放出大量数据
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.rate)
+	l.last = now
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// limitedReader 按 RateLimiter 的速度读取响应内容。
+type limitedReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *RateLimiter
+}
+
+func (r limitedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.wait(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,343 @@
+package engine
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ReceiptsFileName      = "receipts.json"
+	receiptsSchemaVersion = 1
+	AircraftPackageID     = "aircraft"
+)
+
+// ReceiptFile 是安装时写入的一个文件，Path 相对于 Root，使用 / 分隔。
+type ReceiptFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Receipt 记录一次安装写入了哪些文件，卸载、校验和升级都以此为准。
+type Receipt struct {
+	PackageID    string        `json:"package_id"`
+	Name         string        `json:"name"`
+	Version      string        `json:"version,omitempty"`
+	SourceURL    string        `json:"source_url"`
+	ETag         string        `json:"etag,omitempty"`
+	LastModified string        `json:"last_modified,omitempty"`
+	SHA256       string        `json:"sha256,omitempty"`
+	Root         string        `json:"root"`
+	InstalledAt  time.Time     `json:"installed_at"`
+	Files        []ReceiptFile `json:"files"`
+}
+
+type ReceiptDB struct {
+	SchemaVersion int       `json:"schema_version"`
+	Receipts      []Receipt `json:"receipts"`
+}
+
+// receiptsMu 保护 receipts.json 的读改写，批量安装时多个线程会同时写入。
+var receiptsMu sync.Mutex
+
+func LiveryPackageID(id string) string {
+	return "livery:" + id
+}
+
+func SamePath(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	return a == b || strings.EqualFold(a, b) && os.PathSeparator == '\\'
+}
+
+func receiptsPath() (string, error) {
+	return DataPath(ReceiptsFileName)
+}
+
+func LoadReceipts() (*ReceiptDB, error) {
+	p, err := receiptsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &ReceiptDB{SchemaVersion: receiptsSchemaVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var db ReceiptDB
+	if err := json.Unmarshal(data, &db); err != nil {
+		return nil, fmt.Errorf("无法读取 %s: %w", ReceiptsFileName, err)
+	}
+	return &db, nil
+}
+
+func (db *ReceiptDB) save() error {
+	p, err := receiptsPath()
+	if err != nil {
+		return err
+	}
+	db.SchemaVersion = receiptsSchemaVersion
+	data, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	return AtomicWriteFile(p, data, 0644)
+}
+
+func (db *ReceiptDB) Find(packageID, root string) *Receipt {
+	for i := range db.Receipts {
+		if db.Receipts[i].PackageID == packageID && SamePath(db.Receipts[i].Root, root) {
+			return &db.Receipts[i]
+		}
+	}
+	return nil
+}
+
+// ForRoot 返回安装在 root 下的全部收据。
+func (db *ReceiptDB) ForRoot(root string) []Receipt {
+	var out []Receipt
+	for _, r := range db.Receipts {
+		if SamePath(r.Root, root) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Remove 删除 root 中 packageID 的收据。
+func (db *ReceiptDB) Remove(packageID, root string) {
+	kept := db.Receipts[:0]
+	for _, r := range db.Receipts {
+		if r.PackageID == packageID && SamePath(r.Root, root) {
+			continue
+		}
+		kept = append(kept, r)
+	}
+	db.Receipts = kept
+}
+
+// RemoveUnder 删除安装位置在 dir 之内的所有收据，用于整个飞机目录被删除时。
+func (db *ReceiptDB) RemoveUnder(dir string) {
+	dir = filepath.Clean(dir)
+	kept := db.Receipts[:0]
+	for _, r := range db.Receipts {
+		root := filepath.Clean(r.Root)
+		if SamePath(root, dir) || strings.HasPrefix(root, dir+string(os.PathSeparator)) {
+			continue
+		}
+		kept = append(kept, r)
+	}
+	db.Receipts = kept
+}
+
+// UpdateReceipts 在锁内读取、修改并保存收据数据库。
+func UpdateReceipts(fn func(db *ReceiptDB)) error {
+	receiptsMu.Lock()
+	defer receiptsMu.Unlock()
+	db, err := LoadReceipts()
+	if err != nil {
+		return err
+	}
+	fn(db)
+	return db.save()
+}
+
+// RecordReceipt 保存一条收据，替换同一位置上同一个包的旧收据。
+func RecordReceipt(r Receipt) error {
+	sort.Slice(r.Files, func(i, j int) bool { return r.Files[i].Path < r.Files[j].Path })
+	return UpdateReceipts(func(db *ReceiptDB) {
+		db.Remove(r.PackageID, r.Root)
+		db.Receipts = append(db.Receipts, r)
+	})
+}
+
+// PackageSHA256 返回已下载包的哈希：发布者公布过的直接使用（下载时已校验），否则现算。
+func PackageSHA256(src Source, zipPath string) string {
+	if sum := NormalizeSHA256(src.SHA256); sum != "" {
+		return sum
+	}
+	sum, err := FileSHA256(zipPath)
+	if err != nil {
+		return ""
+	}
+	return sum
+}
+
+// extractZipFile 把一个压缩包条目写到 fpath，并返回用于收据的大小和哈希。
+func extractZipFile(ctx context.Context, f *zip.File, fpath string) (ReceiptFile, error) {
+	if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
+		return ReceiptFile{}, err
+	}
+	outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+	if err != nil {
+		return ReceiptFile{}, err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		outFile.Close()
+		return ReceiptFile{}, err
+	}
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(outFile, hasher), contextReader{ctx, rc})
+	outFile.Close()
+	rc.Close()
+	if err != nil {
+		return ReceiptFile{}, err
+	}
+	return ReceiptFile{Path: path.Clean(f.Name), Size: size, SHA256: hex.EncodeToString(hasher.Sum(nil))}, nil
+}
+
+// ReceiptProblem 描述校验时发现的一个与收据不一致的文件。
+type ReceiptProblem struct {
+	Path    string `json:"path"`
+	Missing bool   `json:"missing"`
+}
+
+// VerifyReceipt 逐个检查收据中的文件是否存在且内容未变，每个文件发送一个 PhaseVerify 事件。
+// ctx 被取消时停止并返回 ctx.Err()。
+func VerifyReceipt(ctx context.Context, r Receipt, sink Sink) ([]ReceiptProblem, error) {
+	var problems []ReceiptProblem
+	for i, f := range r.Files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sink.emit(Event{Phase: PhaseVerify, Path: r.Root, File: f.Path, Files: i + 1, TotalFiles: len(r.Files)})
+		full := filepath.Join(r.Root, filepath.FromSlash(f.Path))
+		info, err := os.Stat(full)
+		if err != nil {
+			problems = append(problems, ReceiptProblem{Path: f.Path, Missing: true})
+			continue
+		}
+		if info.Size() != f.Size {
+			problems = append(problems, ReceiptProblem{Path: f.Path})
+			continue
+		}
+		if sum, err := FileSHA256(full); err != nil || sum != f.SHA256 {
+			problems = append(problems, ReceiptProblem{Path: f.Path})
+		}
+	}
+	return problems, nil
+}
+
+// TopLevelDirs 返回收据中文件所在的顶层目录名，用于把涂装文件夹对应到收据。
+func (r Receipt) TopLevelDirs() []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, f := range r.Files {
+		first, _, found := strings.Cut(f.Path, "/")
+		if found && !seen[first] {
+			seen[first] = true
+			dirs = append(dirs, first)
+		}
+	}
+	return dirs
+}
+
+// UninstallReceipt 只删除收据中记录的文件，再删除因此变空的目录；用户自己添加的文件会保留。
+func UninstallReceipt(r Receipt) error {
+	if err := removeFiles(r.Root, r.Files); err != nil {
+		return err
+	}
+	return UpdateReceipts(func(db *ReceiptDB) { db.Remove(r.PackageID, r.Root) })
+}
+
+// UninstallReceiptDir 只删除收据 r 中位于顶层目录 dir 下的文件，再删除因此变空的目录，并把这些文件从收据中去掉；
+// 收据中的其它目录保持安装，没有剩下文件时删除整条收据。读取、删除和保存在同一次加锁内完成。
+func UninstallReceiptDir(r Receipt, dir string) error {
+	var removeErr error
+	err := UpdateReceipts(func(db *ReceiptDB) {
+		current := db.Find(r.PackageID, r.Root)
+		if current == nil {
+			return
+		}
+		var inDir, kept []ReceiptFile
+		for _, f := range current.Files {
+			if first, _, _ := strings.Cut(f.Path, "/"); first == dir {
+				inDir = append(inDir, f)
+			} else {
+				kept = append(kept, f)
+			}
+		}
+		if removeErr = removeFiles(r.Root, inDir); removeErr != nil {
+			return
+		}
+		if len(kept) == 0 {
+			db.Remove(r.PackageID, r.Root)
+		} else {
+			current.Files = kept
+		}
+	})
+	if removeErr != nil {
+		return removeErr
+	}
+	return err
+}
+
+// replaceReceipt 像 RecordReceipt 一样保存 r，并在同一次加锁内删除 r.Root 中旧收据里有、r.Files 里没有的文件，
+// 用于更新后清理旧版本。staleErr 是删除旧文件时遇到的第一个错误，err 是读取或保存收据数据库的错误。
+func replaceReceipt(r Receipt) (staleErr, err error) {
+	sort.Slice(r.Files, func(i, j int) bool { return r.Files[i].Path < r.Files[j].Path })
+	err = UpdateReceipts(func(db *ReceiptDB) {
+		if old := db.Find(r.PackageID, r.Root); old != nil {
+			staleErr = removeFiles(r.Root, staleFiles(old.Files, r.Files))
+		}
+		db.Remove(r.PackageID, r.Root)
+		db.Receipts = append(db.Receipts, r)
+	})
+	return staleErr, err
+}
+
+// staleFiles 返回 old 中有而 current 中没有的文件。
+func staleFiles(old, current []ReceiptFile) []ReceiptFile {
+	kept := make(map[string]bool, len(current))
+	for _, f := range current {
+		kept[f.Path] = true
+	}
+	var stale []ReceiptFile
+	for _, f := range old {
+		if !kept[f.Path] {
+			stale = append(stale, f)
+		}
+	}
+	return stale
+}
+
+// removeFiles 删除 root 中的 files，再删除因此变空的目录，返回遇到的第一个错误。
+func removeFiles(root string, files []ReceiptFile) error {
+	dirs := make(map[string]bool)
+	var firstErr error
+	for _, f := range files {
+		full := filepath.Join(root, filepath.FromSlash(f.Path))
+		if err := os.Remove(full); err != nil && !errors.Is(err, fs.ErrNotExist) && firstErr == nil {
+			firstErr = err
+		}
+		for dir := path.Dir(f.Path); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+	// 先删除较深的目录
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return strings.Count(sorted[i], "/") > strings.Count(sorted[j], "/") })
+	for _, dir := range sorted {
+		os.Remove(filepath.Join(root, filepath.FromSlash(dir))) // 非空目录会删除失败，正好保留
+	}
+	return firstErr
+}
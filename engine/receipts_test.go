@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestUninstallLiveryDirsKeepsOtherFolders 检查卸载一个涂装文件夹时不会删除同一个包安装的其它文件夹。
+func TestUninstallLiveryDirsKeepsOtherFolders(t *testing.T) {
+	liveriesPath := t.TempDir()
+	var files []ReceiptFile
+	for _, p := range []string{"Pack A/objects/a.png", "Pack B/objects/b.png"} {
+		full := filepath.Join(liveriesPath, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("paint"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, ReceiptFile{Path: p, Size: 5})
+	}
+	packageID := LiveryPackageID("two-folders")
+	if err := RecordReceipt(Receipt{PackageID: packageID, Root: liveriesPath, Files: files}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { UpdateReceipts(func(db *ReceiptDB) { db.Remove(packageID, liveriesPath) }) })
+
+	deleted, errs := UninstallLiveryDirs(liveriesPath, []string{"Pack A"})
+	if deleted != 1 || len(errs) != 0 {
+		t.Fatalf("UninstallLiveryDirs = %d, %v", deleted, errs)
+	}
+	if _, err := os.Stat(filepath.Join(liveriesPath, "Pack A")); !os.IsNotExist(err) {
+		t.Errorf("selected folder still present: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(liveriesPath, "Pack B", "objects", "b.png")); err != nil {
+		t.Errorf("folder that was not selected was removed: %v", err)
+	}
+	db, err := LoadReceipts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := db.Find(packageID, liveriesPath)
+	if r == nil || len(r.Files) != 1 || r.Files[0].Path != "Pack B/objects/b.png" {
+		t.Errorf("receipt after uninstall = %+v, want only Pack B", r)
+	}
+
+	if deleted, errs := UninstallLiveryDirs(liveriesPath, []string{"Pack B"}); deleted != 1 || len(errs) != 0 {
+		t.Fatalf("UninstallLiveryDirs = %d, %v", deleted, errs)
+	}
+	if db, _ := LoadReceipts(); db.Find(packageID, liveriesPath) != nil {
+		t.Error("receipt kept after its last folder was uninstalled")
+	}
+}
@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+)
+
+// LiveryState 是目录中的涂装在一个涂装文件夹中的安装状态。
+type LiveryState int
+
+const (
+	LiveryNotInstalled LiveryState = iota
+	LiveryInstalled
+	LiveryOutdated // 已安装，但目录中的版本或包哈希与安装时不同
+)
+
+// LiveryInstall 是 ReconcileLiveries 对一个目录涂装的结论。
+type LiveryInstall struct {
+	State   LiveryState
+	Dirs    []string // 涂装在涂装文件夹中的顶层文件夹
+	Receipt *Receipt // 手动安装的涂装为 nil
+}
+
+// ReconcileLiveries 对照涂装文件夹中的文件夹和安装收据，判断每个目录涂装在 liveriesPath 中的安装状态，按 id 返回。
+//   - 有收据且收据中的文件夹仍在：版本或包哈希与目录不同时为过期，否则为已安装；
+//   - 有收据但文件夹都已被手动删除：未安装；
+//   - 没有收据但有与涂装同名的文件夹（手动安装的）：已安装，无法判断是否过期。
+//
+// liveriesPath 不存在时所有涂装都是未安装。
+func ReconcileLiveries(liveriesPath string, pkgs []LiveryPackage) (map[string]LiveryInstall, error) {
+	result := make(map[string]LiveryInstall, len(pkgs))
+	dirs, err := InstalledLiveryDirs(liveriesPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	onDisk := make(map[string]string, len(dirs)) // 小写名称 → 文件夹名
+	for _, dir := range dirs {
+		onDisk[strings.ToLower(dir)] = dir
+	}
+	db, err := LoadReceipts()
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range pkgs {
+		var install LiveryInstall
+		if r := db.Find(LiveryPackageID(pkg.ID), liveriesPath); r != nil {
+			for _, dir := range r.TopLevelDirs() {
+				if name, ok := onDisk[strings.ToLower(dir)]; ok {
+					install.Dirs = append(install.Dirs, name)
+				}
+			}
+			if len(install.Dirs) > 0 {
+				install.Receipt = r
+				install.State = LiveryInstalled
+				if pkg.outdated(r) {
+					install.State = LiveryOutdated
+				}
+			}
+		} else if name, ok := onDisk[strings.ToLower(strings.TrimSpace(pkg.Name))]; ok {
+			install.Dirs = []string{name}
+			install.State = LiveryInstalled
+		}
+		result[pkg.ID] = install
+	}
+	return result, nil
+}
+
+// outdated 判断按收据 r 安装的涂装与目录中的 l 是否不同：目录和收据都有版本时比较版本，否则比较包哈希。
+func (l LiveryPackage) outdated(r *Receipt) bool {
+	if l.Version != "" && r.Version != "" {
+		return r.Version != l.Version
+	}
+	sum := NormalizeSHA256(l.Source.SHA256)
+	return sum != "" && r.SHA256 != "" && r.SHA256 != sum
+}
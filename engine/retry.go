@@ -0,0 +1,220 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy 决定下载失败后的重试次数与等待时间。
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// maxRetryAfter 限制服务器 Retry-After 的最长等待。
+const maxRetryAfter = 5 * time.Minute
+
+var DownloadRetryPolicy = RetryPolicy{MaxAttempts: 5, BaseDelay: 2 * time.Second, MaxDelay: time.Minute}
+
+var ErrInvalidURL = errors.New("无效的下载 URL")
+
+// HTTPStatusError 表示服务器返回了非预期的状态码。
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("bad status: %s", e.Status)
+}
+
+func newHTTPStatusError(resp *http.Response) *HTTPStatusError {
+	return &HTTPStatusError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+// parseRetryAfter 支持秒数和 HTTP 日期两种格式。
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// RetryAttempt 记录一次失败的尝试。
+type RetryAttempt struct {
+	Attempt int
+	Err     error
+	Delay   time.Duration // 下一次重试前的等待时间
+}
+
+// RetryError 在所有尝试都失败或遇到永久错误时返回，保留完整的尝试历史。
+type RetryError struct {
+	Attempts []RetryAttempt
+}
+
+func (e *RetryError) Error() string {
+	return e.last().Error()
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.last()
+}
+
+func (e *RetryError) last() error {
+	return e.Attempts[len(e.Attempts)-1].Err
+}
+
+// IsTransient 判断错误是否值得重试：超时、连接重置、5xx 等；404、无效 URL 等直接失败。
+func IsTransient(err error) bool {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode == http.StatusRequestTimeout,
+			statusErr.StatusCode == http.StatusTooEarly,
+			statusErr.StatusCode == http.StatusTooManyRequests,
+			statusErr.StatusCode >= 500:
+			return true
+		}
+		return false
+	}
+	if errors.Is(err, ErrInvalidURL) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+	return false
+}
+
+// delay 计算第 attempt 次失败后的等待时间：指数退避加随机抖动，优先使用 Retry-After。
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		return min(statusErr.RetryAfter, maxRetryAfter)
+	}
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	// 在 [d/2, d) 之间抖动，避免多个下载线程同时重试
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// Run 执行 op，直到成功、遇到永久错误或用尽尝试次数。每次准备重试前调用 onRetry。
+// ctx 取消后不再重试，直接返回 ctx.Err()。
+func (p RetryPolicy) Run(ctx context.Context, op func() error, onRetry func(RetryAttempt)) error {
+	maxAttempts := max(p.MaxAttempts, 1)
+	var history []RetryAttempt
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		record := RetryAttempt{Attempt: attempt, Err: err}
+		if attempt >= maxAttempts || !IsTransient(err) {
+			history = append(history, record)
+			return &RetryError{Attempts: history}
+		}
+		record.Delay = p.delay(attempt, err)
+		history = append(history, record)
+		if onRetry != nil {
+			onRetry(record)
+		}
+		select {
+		case <-time.After(record.Delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ErrNotModified 表示服务器上的文件与上次取得的相同（HTTP 304）。
+var ErrNotModified = errors.New("文件没有变化")
+
+// Fetch 按 DownloadRetryPolicy 读取一个较小的远程文件（如涂装列表）的全部内容，每次准备重试前发送 PhaseRetry 事件。
+func Fetch(ctx context.Context, url string, sink Sink) ([]byte, error) {
+	data, _, err := FetchIfChanged(ctx, url, RemotePackageInfo{}, sink)
+	return data, err
+}
+
+// FetchIfChanged 与 Fetch 相同，但带上 prev 中的 ETag 和 Last-Modified 发出条件请求，
+// 服务器上的文件没有变化时返回 ErrNotModified。同时返回本次响应的 ETag 和 Last-Modified，供下次请求使用。
+func FetchIfChanged(ctx context.Context, url string, prev RemotePackageInfo, sink Sink) ([]byte, RemotePackageInfo, error) {
+	policy := DownloadRetryPolicy
+	var data []byte
+	var info RemotePackageInfo
+	err := policy.Run(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return err
+		}
+		if prev.ETag != "" {
+			req.Header.Set("If-None-Match", prev.ETag)
+		}
+		if prev.LastModified != "" {
+			req.Header.Set("If-Modified-Since", prev.LastModified)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		info = RemotePackageInfo{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified"), Size: resp.ContentLength}
+		if resp.StatusCode == http.StatusNotModified {
+			return ErrNotModified
+		}
+		if resp.StatusCode != http.StatusOK {
+			return newHTTPStatusError(resp)
+		}
+		data, err = io.ReadAll(resp.Body)
+		return err
+	}, func(a RetryAttempt) {
+		sink.emit(Event{Phase: PhaseRetry, Attempt: a.Attempt, MaxAttempts: policy.MaxAttempts, Delay: a.Delay, Err: a.Err})
+	})
+	return data, info, err
+}
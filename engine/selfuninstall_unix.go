@@ -0,0 +1,60 @@
+//go:build !windows
+
+package engine
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// shellQuote 用单引号包住参数，参数中的单引号转义为 '\”。
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// appBundleDir 如果程序位于 macOS 的 .app 包内（X.app/Contents/MacOS/程序），返回 .app 的路径。
+func appBundleDir(exePath string) string {
+	macOS := filepath.Dir(exePath)
+	contents := filepath.Dir(macOS)
+	bundle := filepath.Dir(contents)
+	if filepath.Base(macOS) == "MacOS" && filepath.Base(contents) == "Contents" && strings.HasSuffix(bundle, ".app") {
+		return bundle
+	}
+	return ""
+}
+
+// StartSelfUninstall 写一个 shell 脚本，在本程序退出后删除数据文件、下载缓存和程序本身（macOS 上为整个 .app），最后删除脚本自身。
+func StartSelfUninstall(exePath string, dataFiles, dataDirs []string) error {
+	tempScript, err := os.CreateTemp("", "uninstall_*.sh")
+	if err != nil {
+		return err
+	}
+	if _, err := tempScript.WriteString(selfUninstallScript(exePath, dataFiles, dataDirs)); err != nil {
+		tempScript.Close()
+		return err
+	}
+	tempScript.Close()
+	return exec.Command("/bin/sh", tempScript.Name()).Start()
+}
+
+// selfUninstallScript 返回 StartSelfUninstall 运行的脚本。
+func selfUninstallScript(exePath string, dataFiles, dataDirs []string) string {
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\nsleep 2\n")
+	for _, p := range dataFiles {
+		fmt.Fprintf(&script, "rm -f %s\n", shellQuote(p))
+	}
+	for _, p := range dataDirs {
+		fmt.Fprintf(&script, "rm -rf %s\n", shellQuote(p))
+	}
+	if bundle := appBundleDir(exePath); bundle != "" {
+		fmt.Fprintf(&script, "rm -rf %s\n", shellQuote(bundle))
+	} else {
+		fmt.Fprintf(&script, "rm -f %s\n", shellQuote(exePath))
+	}
+	script.WriteString("rm -f \"$0\"\n")
+	return script.String()
+}
@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// StartSelfUninstall 写一个批处理文件，在本程序退出后删除数据文件、下载缓存和程序本身，最后删除批处理自身。
+func StartSelfUninstall(exePath string, dataFiles, dataDirs []string) error {
+	tempBatFile, err := os.CreateTemp("", "uninstall_*.bat")
+	if err != nil {
+		return err
+	}
+	if _, err := tempBatFile.WriteString(selfUninstallScript(exePath, dataFiles, dataDirs)); err != nil {
+		tempBatFile.Close()
+		return err
+	}
+	tempBatFile.Close()
+	return exec.Command("cmd", "/C", "start", "/b", tempBatFile.Name()).Start()
+}
+
+// selfUninstallScript 返回 StartSelfUninstall 运行的批处理。
+func selfUninstallScript(exePath string, dataFiles, dataDirs []string) string {
+	var script strings.Builder
+	script.WriteString("@echo off\ntimeout /t 2 /nobreak > NUL\n")
+	for _, p := range dataFiles {
+		fmt.Fprintf(&script, "del \"%s\"\n", p)
+	}
+	for _, p := range dataDirs {
+		fmt.Fprintf(&script, "rmdir /s /q \"%s\"\n", p)
+	}
+	fmt.Fprintf(&script, "del \"%s\"\n", exePath)
+	script.WriteString("(goto) 2>nul & del \"%~f0\"\n")
+	return script.String()
+}
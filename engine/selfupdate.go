@@ -0,0 +1,199 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// AfterUpdateFlag 由旧进程传给新进程，值为旧程序被改名后的路径，新进程启动后负责删除它。
+const AfterUpdateFlag = "--after-update"
+
+// 新进程在这段时间内没有报告启动成功就回滚到旧版本。
+const selfUpdateStartTimeout = 30 * time.Second
+
+// ErrUnverifiedUpdate 表示发布者没有公布新版本的 SHA-256。无法确认下载的就是发布的程序时不替换正在运行的程序。
+var ErrUnverifiedUpdate = errors.New("版本清单没有公布新版本的 SHA-256，无法校验")
+
+// selfUpdatePaths 返回自更新过程中用到的文件：新版本先下载到 .new，旧版本改名为 .old，
+// 新进程启动成功后创建 .started 通知旧进程退出。
+func selfUpdatePaths(exePath string) (newPath, oldPath, startedPath string) {
+	return exePath + ".new", exePath + ".old", exePath + ".started"
+}
+
+// checkExecutable 粗略检查文件是否为当前平台的可执行文件。内容已经按 SHA-256 校验过，这里只是防止发布了错误平台的程序。
+func checkExecutable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	header := make([]byte, 4)
+	if _, err := f.Read(header); err != nil {
+		return fmt.Errorf("无法读取新版本: %w", err)
+	}
+	for _, magic := range executableMagics {
+		if bytes.HasPrefix(header, magic) {
+			return nil
+		}
+	}
+	return errors.New("下载的文件不是有效的可执行程序")
+}
+
+// swapExecutable 把正在运行的程序改名为 .old，再把 .new 改名为原文件名。
+// 正在运行的程序在 Windows 上不能删除但可以改名。
+func swapExecutable(exePath, newPath, oldPath string) error {
+	os.Remove(oldPath)
+	if err := os.Rename(exePath, oldPath); err != nil {
+		return fmt.Errorf("无法移动当前程序: %w", err)
+	}
+	if err := makeExecutable(newPath); err != nil {
+		os.Rename(oldPath, exePath)
+		return err
+	}
+	if err := os.Rename(newPath, exePath); err != nil {
+		os.Rename(oldPath, exePath)
+		return fmt.Errorf("无法放置新版本: %w", err)
+	}
+	return nil
+}
+
+// restoreExecutable 在新版本无法启动时恢复旧版本。
+func restoreExecutable(exePath, oldPath string) error {
+	os.Remove(exePath)
+	return os.Rename(oldPath, exePath)
+}
+
+// launchUpdatedExecutable 启动新版本并等待它报告启动成功，args 会附加在命令行后面（命令行模式下为要执行的命令）。
+// 新进程提前退出或超时都视为失败，此时结束新进程并恢复旧版本。
+func launchUpdatedExecutable(exePath, oldPath, startedPath string, args ...string) error {
+	os.Remove(startedPath)
+	cmd := exec.Command(exePath, append([]string{AfterUpdateFlag, oldPath}, args...)...)
+	if err := cmd.Start(); err != nil {
+		if restoreErr := restoreExecutable(exePath, oldPath); restoreErr != nil {
+			return fmt.Errorf("无法启动新版本: %w; 恢复旧版本失败: %v", err, restoreErr)
+		}
+		return fmt.Errorf("无法启动新版本: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.After(selfUpdateStartTimeout)
+	var cause error
+	for cause == nil {
+		select {
+		case <-ticker.C:
+			if pathExists(startedPath) {
+				os.Remove(startedPath)
+				return nil
+			}
+		case err := <-exited:
+			if pathExists(startedPath) {
+				os.Remove(startedPath)
+				return nil
+			}
+			cause = fmt.Errorf("新版本启动后立即退出: %v", err)
+		case <-deadline:
+			cmd.Process.Kill()
+			<-exited
+			cause = errors.New("新版本没有在规定时间内启动")
+		}
+	}
+	if err := restoreExecutable(exePath, oldPath); err != nil {
+		return fmt.Errorf("%w; 恢复旧版本失败: %v", cause, err)
+	}
+	return cause
+}
+
+// SelfUpdate 下载新版本、替换当前程序并启动新版本，新版本无法启动时恢复旧版本。
+// src 没有 SHA-256 时返回 ErrUnverifiedUpdate 而不下载，除非 allowUnverified 表示用户已经确认安装未经校验的版本；
+// 这时下载完成后发送 PhaseUnverified 事件，只检查文件是可执行程序。
+// launchArgs 附加在新进程的命令行后面。返回 nil 时新版本已经在运行，调用者应当退出。
+// 只有下载和校验阶段可以取消，开始替换程序后不再响应 ctx。
+func SelfUpdate(ctx context.Context, src Source, allowUnverified bool, sink Sink, launchArgs ...string) error {
+	if NormalizeSHA256(src.SHA256) == "" && !allowUnverified {
+		return fail(sink, PhaseVerify, ErrUnverifiedUpdate)
+	}
+	exePath, err := os.Executable()
+	if err != nil {
+		return fail(sink, PhasePrepare, err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+		exePath = resolved
+	}
+	newPath, oldPath, startedPath := selfUpdatePaths(exePath)
+	if _, err := Download(ctx, src, newPath, sink); err != nil {
+		return fail(sink, PhaseDownload, err)
+	}
+	sink.emit(Event{Phase: PhaseVerify, Path: newPath})
+	err = checkExecutable(newPath)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		os.Remove(newPath)
+		return fail(sink, PhaseVerify, err)
+	}
+
+	sink.emit(Event{Phase: PhaseReplace, Path: exePath})
+	if err := swapExecutable(exePath, newPath, oldPath); err != nil {
+		os.Remove(newPath)
+		return fail(sink, PhaseReplace, err)
+	}
+	sink.emit(Event{Phase: PhaseLaunch, Path: exePath})
+	if err := launchUpdatedExecutable(exePath, oldPath, startedPath, launchArgs...); err != nil {
+		return fail(sink, PhaseLaunch, err)
+	}
+	return nil
+}
+
+// AfterUpdateOldPath 如果本进程是由自更新启动的，返回旧程序的路径。
+func AfterUpdateOldPath() string {
+	for i, arg := range os.Args[1:] {
+		if arg == AfterUpdateFlag && i+2 < len(os.Args) {
+			return os.Args[i+2]
+		}
+	}
+	return ""
+}
+
+// FinishSelfUpdate 由新进程调用：通知旧进程启动成功，然后在后台等旧进程退出后删除旧程序。
+// version 是新进程的版本号，写入 .started 文件。新进程先于旧进程退出时（例如命令行模式下执行完一个命令）
+// 来不及删除，由下次启动时的 RemoveOldExecutable 删除。
+func FinishSelfUpdate(oldPath, version string) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return
+	}
+	_, _, startedPath := selfUpdatePaths(exePath)
+	os.WriteFile(startedPath, []byte(version), 0644)
+	go func() {
+		// 旧进程看到 .started 后才会退出，Windows 上在此之前无法删除它
+		for i := 0; i < 60; i++ {
+			if err := os.Remove(oldPath); err == nil || os.IsNotExist(err) {
+				return
+			}
+			time.Sleep(time.Second)
+		}
+	}()
+}
+
+// RemoveOldExecutable 删除上次自更新留下的旧程序，不是由自更新启动时在启动时调用。
+func RemoveOldExecutable() {
+	exePath, err := os.Executable()
+	if err != nil {
+		return
+	}
+	if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+		exePath = resolved
+	}
+	_, oldPath, _ := selfUpdatePaths(exePath)
+	os.Remove(oldPath)
+}
@@ -0,0 +1,21 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestSelfUpdateRequiresChecksum 检查没有公布 SHA-256 时不下载也不替换程序。
+func TestSelfUpdateRequiresChecksum(t *testing.T) {
+	var events []Event
+	err := SelfUpdate(context.Background(), Source{URL: "https://example.com/installer"}, false, func(e Event) { events = append(events, e) })
+	if !errors.Is(err, ErrUnverifiedUpdate) {
+		t.Fatalf("SelfUpdate = %v, want ErrUnverifiedUpdate", err)
+	}
+	for _, e := range events {
+		if e.Phase == PhaseDownload || e.Phase == PhaseReplace {
+			t.Errorf("unexpected %s event before checksum check", e.Phase)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 飞机包中携带版本号的文件，按顺序查找，第一行非空内容即为版本号。
+var aircraftVersionFiles = []string{"AG330_version.txt", "version.txt"}
+
+// RemotePackageInfo 是服务器上当前发布的包的标识，用于判断是否有更新。
+type RemotePackageInfo struct {
+	ETag         string
+	LastModified string
+	Size         int64
+}
+
+// Published 返回发布时间，服务器未提供时返回零值。
+func (info RemotePackageInfo) Published() time.Time {
+	t, _ := http.ParseTime(info.LastModified)
+	return t
+}
+
+// SameAs 判断服务器上的包是否就是收据记录的那个包。
+func (info RemotePackageInfo) SameAs(r *Receipt) bool {
+	switch {
+	case info.ETag != "" && r.ETag != "":
+		return info.ETag == r.ETag
+	case info.LastModified != "" && r.LastModified != "":
+		return info.LastModified == r.LastModified
+	}
+	return true // 无法比较时不提示更新
+}
+
+// ReadVersionMarker 读取包内的版本文件。
+func ReadVersionMarker(dir string) string {
+	for _, name := range aircraftVersionFiles {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				f.Close()
+				return line
+			}
+		}
+		f.Close()
+	}
+	return ""
+}
+
+// DetectAircraftVersion 依次使用包内版本文件和安装收据得到已安装的版本号。
+func DetectAircraftVersion(dir string) string {
+	if v := ReadVersionMarker(dir); v != "" {
+		return v
+	}
+	db, err := LoadReceipts()
+	if err != nil {
+		return ""
+	}
+	if r := db.Find(AircraftPackageID, dir); r != nil {
+		return r.Version
+	}
+	return ""
+}
+
+// ProbeRemotePackage 只请求第一个字节来获取包的 ETag、Last-Modified 和大小，不下载整个文件。
+func ProbeRemotePackage(ctx context.Context, src Source) (RemotePackageInfo, error) {
+	if err := checkDownloadURL(src.URL); err != nil {
+		return RemotePackageInfo{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", src.URL, nil)
+	if err != nil {
+		return RemotePackageInfo{}, err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return RemotePackageInfo{}, err
+	}
+	defer resp.Body.Close()
+	info := RemotePackageInfo{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		if _, total, ok := parseContentRange(resp.Header.Get("Content-Range")); ok {
+			info.Size = total
+		}
+	case http.StatusOK:
+		info.Size = resp.ContentLength
+	default:
+		return RemotePackageInfo{}, newHTTPStatusError(resp)
+	}
+	return info, nil
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"myapp/engine"
+)
+
+// galleryNameLength 是画廊中涂装名称显示的最大字符数，格子宽度固定，过长的名称会被截断。
+const galleryNameLength = 26
+
+// createLiveryBrowser 创建涂装页上方的目录浏览：搜索框、筛选、分组、排序，可勾选的涂装列表或画廊，
+// 以及右侧显示所点涂装大图和信息的预览栏。
+// 筛选条件和视图保存在 state 中，界面重建后保持不变。
+func createLiveryBrowser(state *AppState) fyne.CanvasObject {
+	installs, reconcileErr := liveryInstalls(state)
+	state.catalog = newCatalogModel(state.liveries, installs)
+	state.catalog.setFilter(state.liveryFilter)
+
+	previewImage := newPreviewImage(fyne.NewSize(320, 180))
+	previewTitle := widget.NewLabelWithStyle(state.tr("preview_none_label"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	previewTitle.Wrapping = fyne.TextWrapWord
+	previewDetail := widget.NewLabel("")
+	previewDetail.Wrapping = fyne.TextWrapWord
+	var focused atomic.Pointer[Livery] // 预览栏中的涂装，后台下载完成时也会读取
+	showPreview := func() {
+		l := focused.Load()
+		if l == nil {
+			return
+		}
+		setPreviewImage(previewImage, state.previews, *l)
+		previewTitle.SetText(l.Name)
+		previewDetail.SetText(liveryDetailText(state, *l))
+	}
+	focus := func(l *Livery) {
+		focused.Store(l)
+		showPreview()
+	}
+
+	state.catalogList = widget.NewList(
+		func() int { return len(state.catalog.rows) },
+		func() fyne.CanvasObject {
+			header := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+			source := widget.NewLabel("")
+			source.Importance = widget.LowImportance
+			return container.NewStack(header, container.NewBorder(nil, nil, nil, container.NewHBox(source, widget.NewLabel("")), widget.NewCheck("", nil)))
+		},
+		func(i widget.ListItemID, item fyne.CanvasObject) {
+			if i >= len(state.catalog.rows) {
+				return
+			}
+			row := state.catalog.rows[i]
+			objects := item.(*fyne.Container).Objects
+			header, entry := objects[0].(*widget.Label), objects[1].(*fyne.Container)
+			check, labels := entry.Objects[0].(*widget.Check), entry.Objects[1].(*fyne.Container)
+			source, badge := labels.Objects[0].(*widget.Label), labels.Objects[1].(*widget.Label)
+			if row.Livery == nil {
+				airline := row.Header
+				if airline == "" {
+					airline = state.tr("no_airline_group")
+				}
+				header.SetText(state.tr("airline_group_label", airline, row.Count))
+				header.Show()
+				entry.Hide()
+				return
+			}
+			header.Hide()
+			entry.Show()
+			id := row.Livery.ID
+			source.SetText(row.Livery.Source)
+			setStateBadge(state, badge, state.catalog.state(id))
+			// 先去掉回调，避免复用行时 SetChecked 改动其它涂装的勾选
+			check.OnChanged = nil
+			check.SetText(liveryRowText(*row.Livery))
+			check.SetChecked(state.catalog.selected[id])
+			check.OnChanged = func(on bool) {
+				state.catalog.setSelected(id, on)
+				onLiverySelectionChanged(state)
+			}
+		},
+	)
+	state.catalogList.OnSelected = func(i widget.ListItemID) {
+		if row := state.catalog.rows[i]; row.Livery != nil {
+			focus(row.Livery)
+		} else {
+			state.catalogList.Unselect(i)
+		}
+	}
+
+	state.catalogGallery = widget.NewGridWrap(
+		func() int { return len(state.catalog.visible) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(widget.NewLabel(""), widget.NewCheck("", nil), nil, nil, newPreviewImage(fyne.NewSize(192, 108)))
+		},
+		func(i widget.GridWrapItemID, item fyne.CanvasObject) {
+			if i >= len(state.catalog.visible) {
+				return
+			}
+			l := state.catalog.visible[i]
+			objects := item.(*fyne.Container).Objects
+			image, badge, check := objects[0].(*canvas.Image), objects[1].(*widget.Label), objects[2].(*widget.Check)
+			setPreviewImage(image, state.previews, *l)
+			id := l.ID
+			setStateBadge(state, badge, state.catalog.state(id))
+			check.OnChanged = nil
+			check.SetText(truncateText(l.Name, galleryNameLength))
+			check.SetChecked(state.catalog.selected[id])
+			check.OnChanged = func(on bool) {
+				state.catalog.setSelected(id, on)
+				onLiverySelectionChanged(state)
+			}
+		},
+	)
+	state.catalogGallery.OnSelected = func(i widget.GridWrapItemID) {
+		focus(state.catalog.visible[i])
+	}
+	state.previews.setLocal(localPreviews(state.catalog.installs))
+	state.previews.setOnLoaded(func(id string) {
+		state.catalogGallery.Refresh()
+		if l := focused.Load(); l != nil && l.ID == id {
+			showPreview()
+		}
+	})
+	showView := func() {
+		if state.liveryGallery {
+			state.catalogList.Hide()
+			state.catalogGallery.Show()
+		} else {
+			state.catalogGallery.Hide()
+			state.catalogList.Show()
+		}
+	}
+	showView()
+
+	countLabel := widget.NewLabel("")
+	update := func() {
+		state.catalog.setFilter(state.liveryFilter)
+		state.catalogList.UnselectAll()
+		state.catalogList.Refresh()
+		state.catalogList.ScrollToTop()
+		state.catalogGallery.UnselectAll()
+		state.catalogGallery.Refresh()
+		state.catalogGallery.ScrollToTop()
+		countLabel.SetText(state.tr("catalog_count_label", state.catalog.visibleCount(), len(state.liveries)))
+	}
+	countLabel.SetText(state.tr("catalog_count_label", state.catalog.visibleCount(), len(state.liveries)))
+	state.reconcileLabel = widget.NewLabel("")
+	state.reconcileLabel.Importance = widget.DangerImportance
+	showReconcileError(state, reconcileErr)
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder(state.tr("livery_search_placeholder"))
+	searchEntry.SetText(state.liveryFilter.Query)
+	searchEntry.OnChanged = func(query string) {
+		state.liveryFilter.Query = query
+		update()
+	}
+	realSelect := newIndexSelect([]string{state.tr("filter_all_types"), state.tr("filter_real"), state.tr("filter_fictional")}, int(state.liveryFilter.Real), func(i int) {
+		state.liveryFilter.Real = realFilter(i)
+		update()
+	})
+	installedSelect := newIndexSelect([]string{state.tr("filter_all_install_states"), state.tr("filter_installed"), state.tr("filter_not_installed")}, int(state.liveryFilter.Installed), func(i int) {
+		state.liveryFilter.Installed = installedFilter(i)
+		update()
+	})
+	sortSelect := newIndexSelect([]string{state.tr("sort_by_name"), state.tr("sort_by_airline"), state.tr("sort_by_registration")}, int(state.liveryFilter.Sort), func(i int) {
+		state.liveryFilter.Sort = liverySort(i)
+		update()
+	})
+	updatesCheck := widget.NewCheck(state.tr("filter_updates_only"), nil)
+	updatesCheck.SetChecked(state.liveryFilter.UpdatesOnly)
+	updatesCheck.OnChanged = func(on bool) {
+		state.liveryFilter.UpdatesOnly = on
+		update()
+	}
+	groupCheck := widget.NewCheck(state.tr("group_by_airline"), nil)
+	groupCheck.SetChecked(state.liveryFilter.GroupByAirline)
+	groupCheck.OnChanged = func(on bool) {
+		state.liveryFilter.GroupByAirline = on
+		update()
+	}
+	galleryCheck := widget.NewCheck(state.tr("gallery_view_check"), func(on bool) {
+		state.liveryGallery = on
+		showView()
+	})
+	galleryCheck.SetChecked(state.liveryGallery)
+	selectShownBtn := widget.NewButton(state.tr("select_shown_button"), func() {
+		state.catalog.selectVisible(true)
+		refreshCatalogViews(state)
+		onLiverySelectionChanged(state)
+	})
+	clearSelectionBtn := widget.NewButton(state.tr("clear_selection_button"), func() {
+		state.catalog.clearSelection()
+		refreshCatalogViews(state)
+		onLiverySelectionChanged(state)
+	})
+
+	top := container.NewVBox(
+		searchEntry,
+		container.NewGridWithColumns(3, realSelect, installedSelect, sortSelect),
+		container.NewHBox(updatesCheck, groupCheck, galleryCheck, layout.NewSpacer(), state.reconcileLabel, countLabel, selectShownBtn, clearSelectionBtn),
+	)
+	previewPane := container.NewBorder(nil, container.NewVBox(previewTitle, previewDetail), nil, nil, previewImage)
+	split := container.NewHSplit(container.NewStack(state.catalogList, state.catalogGallery), previewPane)
+	split.Offset = 0.65
+	return container.NewBorder(top, nil, nil, nil, split)
+}
+
+// setStateBadge 在 badge 中显示涂装的安装状态。
+func setStateBadge(state *AppState, badge *widget.Label, s engine.LiveryState) {
+	switch s {
+	case engine.LiveryInstalled:
+		badge.Importance = widget.SuccessImportance
+		badge.SetText(state.tr("badge_installed"))
+	case engine.LiveryOutdated:
+		badge.Importance = widget.WarningImportance
+		badge.SetText(state.tr("badge_outdated"))
+	default:
+		badge.Importance = widget.LowImportance
+		badge.SetText(state.tr("badge_not_installed"))
+	}
+}
+
+// refreshLiveryStates 在安装或卸载涂装后重新对照涂装文件夹，更新状态标记、筛选结果和"更新过期涂装"按钮。
+func refreshLiveryStates(state *AppState) {
+	if state.catalog == nil {
+		return
+	}
+	installs, err := liveryInstalls(state)
+	showReconcileError(state, err)
+	state.catalog.installs = installs
+	state.catalog.apply()
+	state.previews.setLocal(localPreviews(state.catalog.installs))
+	refreshCatalogViews(state)
+	refreshUpdateOutdatedButton(state)
+}
+
+// showReconcileError 在涂装数量旁显示对照涂装文件夹的错误，没有错误时隐藏。
+func showReconcileError(state *AppState, err error) {
+	if err == nil {
+		state.reconcileLabel.Hide()
+		return
+	}
+	state.reconcileLabel.SetText(state.tr("livery_reconcile_error", err))
+	state.reconcileLabel.Show()
+}
+
+// refreshUpdateOutdatedButton 在按钮上显示过期涂装的数量，没有时禁用按钮。
+func refreshUpdateOutdatedButton(state *AppState) {
+	n := len(state.catalog.outdatedLiveries())
+	state.updateOutdatedBtn.SetText(state.tr("update_outdated_button", n))
+	if n > 0 {
+		state.updateOutdatedBtn.Enable()
+	} else {
+		state.updateOutdatedBtn.Disable()
+	}
+}
+
+// refreshCatalogViews 在勾选变化后刷新列表和画廊。
+func refreshCatalogViews(state *AppState) {
+	state.catalogList.Refresh()
+	state.catalogGallery.Refresh()
+}
+
+// newPreviewImage 创建按比例缩放显示预览图的图片框。
+func newPreviewImage(minSize fyne.Size) *canvas.Image {
+	image := canvas.NewImageFromResource(theme.FileImageIcon())
+	image.FillMode = canvas.ImageFillContain
+	image.SetMinSize(minSize)
+	return image
+}
+
+// setPreviewImage 在图片框中显示涂装的预览图，正在下载时显示下载图标，没有预览图时显示占位图标。
+func setPreviewImage(image *canvas.Image, previews *previewLoader, l Livery) {
+	path, ready := previews.path(l)
+	switch {
+	case path != "":
+		image.Resource, image.File = nil, path
+	case !ready:
+		image.Resource, image.File = theme.DownloadIcon(), ""
+	default:
+		image.Resource, image.File = theme.FileImageIcon(), ""
+	}
+	image.Refresh()
+}
+
+// liveryDetailText 返回预览栏中涂装的信息，每项一行，空的项不显示。
+func liveryDetailText(state *AppState, l Livery) string {
+	var lines []string
+	if l.Airline != "" {
+		airline := l.Airline
+		if l.ICAO != "" {
+			airline += " (" + l.ICAO + ")"
+		}
+		lines = append(lines, state.tr("preview_airline_label", airline))
+	}
+	if l.Registration != "" {
+		lines = append(lines, state.tr("preview_registration_label", l.Registration))
+	}
+	if l.Version != "" {
+		lines = append(lines, state.tr("preview_version_label", l.Version))
+	}
+	if l.Author != "" {
+		lines = append(lines, state.tr("preview_author_label", l.Author))
+	}
+	if l.Source != "" {
+		lines = append(lines, state.tr("preview_source_label", l.Source))
+	}
+	if l.Real {
+		lines = append(lines, state.tr("preview_real_label"))
+	} else {
+		lines = append(lines, state.tr("preview_fictional_label"))
+	}
+	install := state.catalog.installs[l.ID]
+	switch install.State {
+	case engine.LiveryOutdated:
+		if install.Receipt.Version != "" {
+			lines = append(lines, state.tr("preview_outdated_version_label", install.Receipt.Version))
+		} else {
+			lines = append(lines, state.tr("preview_outdated_label"))
+		}
+	case engine.LiveryInstalled:
+		lines = append(lines, state.tr("preview_installed_label", strings.Join(install.Dirs, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncateText 把超过 n 个字符的文字截断并加上省略号。
+func truncateText(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+// newIndexSelect 创建按序号取值的下拉框，选项是翻译后的文字，不能用来区分选项。
+func newIndexSelect(options []string, selected int, onChanged func(int)) *widget.Select {
+	s := widget.NewSelect(options, nil)
+	s.SetSelectedIndex(selected)
+	s.OnChanged = func(string) { onChanged(s.SelectedIndex()) }
+	return s
+}
+
+// liveryRowText 返回列表中涂装的说明：名称，后面附上与名称不同的注册号和航空公司。
+func liveryRowText(l Livery) string {
+	parts := []string{l.Name}
+	for _, extra := range []string{l.Registration, l.Airline} {
+		if extra != "" && !strings.Contains(l.Name, extra) {
+			parts = append(parts, extra)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// onLiverySelectionChanged 按勾选的涂装数更新安装按钮和状态栏。
+func onLiverySelectionChanged(state *AppState) {
+	if n := len(state.catalog.selectedLiveries()); n > 0 {
+		state.installLiveryBtn.Enable()
+		state.statusLabel.SetText(state.tr("liveries_selected_status", n))
+	} else {
+		state.installLiveryBtn.Disable()
+		state.statusLabel.SetText(state.tr("no_livery_selected_status"))
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"myapp/engine"
+)
+
+// createQueuePanel 创建涂装页下方的安装队列：每个任务一行，显示状态、下载进度或错误，可以暂停、继续、调整顺序或删除。
+func createQueuePanel(state *AppState) fyne.CanvasObject {
+	state.queueJobs = state.liveryQueue.Jobs()
+	state.queueList = widget.NewList(
+		func() int { return len(state.queueJobs) },
+		func() fyne.CanvasObject {
+			buttons := container.NewHBox(
+				widget.NewButtonWithIcon("", theme.MoveUpIcon(), nil),
+				widget.NewButtonWithIcon("", theme.MoveDownIcon(), nil),
+				widget.NewButtonWithIcon("", theme.MediaPauseIcon(), nil),
+				widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+			)
+			detail := widget.NewLabel("")
+			detail.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil, buttons, container.NewVBox(widget.NewLabel(""), detail))
+		},
+		func(i widget.ListItemID, item fyne.CanvasObject) {
+			if i >= len(state.queueJobs) {
+				return
+			}
+			job := state.queueJobs[i]
+			row := item.(*fyne.Container)
+			labels := row.Objects[0].(*fyne.Container).Objects
+			labels[0].(*widget.Label).SetText(state.tr("queue_row_label", job.Livery.Name, state.tr("job_state_"+string(job.State))))
+			labels[1].(*widget.Label).SetText(queueJobDetail(state, job))
+			buttons := row.Objects[1].(*fyne.Container).Objects
+			up, down, toggle, remove := buttons[0].(*widget.Button), buttons[1].(*widget.Button), buttons[2].(*widget.Button), buttons[3].(*widget.Button)
+			id := job.ID()
+			up.OnTapped = func() { state.liveryQueue.Move(id, -1) }
+			down.OnTapped = func() { state.liveryQueue.Move(id, 1) }
+			remove.OnTapped = func() { state.liveryQueue.Remove(id) }
+			switch job.State {
+			case engine.JobPaused:
+				toggle.SetIcon(theme.MediaPlayIcon())
+				toggle.OnTapped = func() {
+					state.liveryQueue.Resume(id)
+					runLiveryQueue(state)
+				}
+				toggle.Enable()
+			case engine.JobQueued, engine.JobRunning:
+				toggle.SetIcon(theme.MediaPauseIcon())
+				toggle.OnTapped = func() { state.liveryQueue.Pause(id) }
+				toggle.Enable()
+			default:
+				toggle.SetIcon(theme.MediaPauseIcon())
+				toggle.OnTapped = nil
+				toggle.Disable()
+			}
+		},
+	)
+	startBtn := widget.NewButtonWithIcon(state.tr("queue_start_button"), theme.MediaPlayIcon(), func() { runLiveryQueue(state) })
+	retryBtn := widget.NewButtonWithIcon(state.tr("queue_retry_failed_button"), theme.ViewRefreshIcon(), func() { retryFailedLiveries(state) })
+	clearBtn := widget.NewButton(state.tr("queue_clear_finished_button"), func() { state.liveryQueue.ClearFinished() })
+	header := container.NewBorder(nil, nil, widget.NewLabelWithStyle(state.tr("queue_title"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), container.NewHBox(startBtn, retryBtn, clearBtn))
+	return container.NewBorder(header, nil, nil, nil, state.queueList)
+}
+
+// refreshQueueList 在队列变化后更新列表。
+func refreshQueueList(state *AppState) {
+	if state.queueList == nil {
+		return
+	}
+	state.queueJobs = state.liveryQueue.Jobs()
+	state.queueList.Refresh()
+}
+
+// queueJobDetail 返回任务行第二行的文字：安装中显示最近的下载或解压进度，失败时显示错误。
+func queueJobDetail(state *AppState, job engine.QueueJob) string {
+	state.queueProgressMu.Lock()
+	e, ok := state.queueProgress[job.ID()]
+	state.queueProgressMu.Unlock()
+	switch job.State {
+	case engine.JobRunning:
+		if ok {
+			return describeEvent(state, e)
+		}
+	case engine.JobFailed:
+		// 本次运行中失败的任务有原始错误，可以显示为当前语言；重启后只有保存的文字
+		if ok && e.Phase == engine.PhaseError {
+			return localizeError(state, e.Err).Error()
+		}
+		return job.Error
+	}
+	return ""
+}
+
+// recordQueueEvent 记下任务最近的事件并只刷新这一行。
+func recordQueueEvent(state *AppState, e engine.Event) {
+	state.queueProgressMu.Lock()
+	if state.queueProgress == nil {
+		state.queueProgress = make(map[string]engine.Event)
+	}
+	state.queueProgress[e.Item] = e
+	state.queueProgressMu.Unlock()
+	if state.queueList == nil {
+		return
+	}
+	for i, job := range state.queueJobs {
+		if job.ID() == e.Item {
+			state.queueList.RefreshItem(i)
+			return
+		}
+	}
+}
+
+// queueProgressText 返回开始安装某个任务时显示的“第几个，共几个”。暂停的任务不计入总数。
+func queueProgressText(state *AppState, id string) string {
+	var started, total int
+	for _, job := range state.liveryQueue.Jobs() {
+		switch job.State {
+		case engine.JobPaused:
+			continue
+		case engine.JobDone, engine.JobFailed, engine.JobRunning:
+			started++
+		}
+		total++
+	}
+	return state.tr("batch_download_progress_label", started, total, queueJobName(state, id))
+}
+
+func queueJobName(state *AppState, id string) string {
+	for _, job := range state.liveryQueue.Jobs() {
+		if job.ID() == id {
+			return job.Livery.Name
+		}
+	}
+	return id
+}
+
+// retryFailedLiveries 让失败的涂装重新排队并开始安装。
+func retryFailedLiveries(state *AppState) {
+	if state.liveryQueue.RetryFailed() > 0 {
+		runLiveryQueue(state)
+	}
+}
+
+// queueReport 返回本次运行中完成和失败的任务：状态与开始时不同的已结束任务，包括运行期间加入的。
+func queueReport(before map[string]engine.JobState, jobs []engine.QueueJob) (succeeded, failed []engine.QueueJob) {
+	for _, job := range jobs {
+		if before[job.ID()] == job.State {
+			continue
+		}
+		switch job.State {
+		case engine.JobDone:
+			succeeded = append(succeeded, job)
+		case engine.JobFailed:
+			failed = append(failed, job)
+		}
+	}
+	return succeeded, failed
+}
+
+// showQueueReport 分别列出安装成功和失败的涂装；有失败时可以直接重试失败的涂装。
+func showQueueReport(state *AppState, succeeded, failed []engine.QueueJob) {
+	if len(failed) == 0 {
+		dialog.ShowInformation(state.tr("install_success_title"), state.tr("batch_install_complete_message", len(succeeded)), state.mainWindow)
+		return
+	}
+	var lines []string
+	if len(succeeded) > 0 {
+		lines = append(lines, state.tr("report_succeeded_label", len(succeeded)))
+		for _, job := range succeeded {
+			lines = append(lines, "  "+job.Livery.Name)
+		}
+		lines = append(lines, "")
+	}
+	lines = append(lines, state.tr("report_failed_label", len(failed)))
+	for _, job := range failed {
+		lines = append(lines, "  "+job.Livery.Name+": "+queueJobDetail(state, job))
+	}
+	text := widget.NewLabel(strings.Join(lines, "\n"))
+	text.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(text)
+	scroll.SetMinSize(fyne.NewSize(460, 240))
+	d := dialog.NewCustomConfirm(state.tr("report_title"), state.tr("queue_retry_failed_button"), state.tr("close_button"), scroll, func(retry bool) {
+		if retry {
+			retryFailedLiveries(state)
+		}
+	}, state.mainWindow)
+	d.Show()
+}
+
+// runLiveryQueue 在后台用多个线程安装队列中等待的涂装；队列已在运行时新加入或继续的任务会被正在运行的线程取走。
+func runLiveryQueue(state *AppState) {
+	if state.liveryQueue.Pending() == 0 || state.queueRunning.Swap(true) {
+		return
+	}
+	state.updateListBtn.Disable()
+	state.uninstallBtn.Disable()
+	ctx := startOperation(state)
+	before := make(map[string]engine.JobState)
+	for _, job := range state.liveryQueue.Jobs() {
+		before[job.ID()] = job.State
+	}
+
+	go func() {
+		defer func() {
+			state.queueRunning.Store(false)
+			finishOperation(state)
+			state.updateListBtn.Enable()
+			state.uninstallBtn.Enable()
+			refreshLiveryStates(state)
+			if ctx.Err() != nil {
+				state.statusLabel.SetText(state.tr("operation_cancelled_status"))
+				return
+			}
+			succeeded, failed := queueReport(before, state.liveryQueue.Jobs())
+			state.statusLabel.SetText(state.tr("batch_install_report_status", len(succeeded), len(failed)))
+			showQueueReport(state, succeeded, failed)
+		}()
+
+		// 创建一个通道来传递状态更新
+		statusUpdates := make(chan string, 100)
+		progressUpdates := make(chan float64, 100)
+
+		// 启动一个 goroutine 来处理所有 UI 更新，结束时关闭 drained
+		drained := make(chan struct{})
+		go func() {
+			defer close(drained)
+			for {
+				select {
+				case status, ok := <-statusUpdates:
+					if !ok {
+						return
+					}
+					state.statusLabel.SetText(status)
+				case progress, ok := <-progressUpdates:
+					if !ok {
+						return
+					}
+					state.progressBar.SetValue(progress)
+				}
+			}
+		}()
+
+		rep := channelReporter{statusUpdates: statusUpdates, progressUpdates: progressUpdates}
+		sink := eventSink(state, rep)
+		queueSink := func(e engine.Event) {
+			if e.Item != "" {
+				recordQueueEvent(state, e)
+			}
+			switch e.Phase {
+			case engine.PhasePrepare:
+				rep.Status(queueProgressText(state, e.Item))
+				rep.Progress(0)
+			case engine.PhaseError:
+				rep.Status(state.tr("livery_download_failed_status", queueJobName(state, e.Item), localizeError(state, e.Err)))
+			default:
+				sink(e)
+			}
+		}
+		// 线程都退出后才继续的任务由下一轮安装
+		for ctx.Err() == nil && state.liveryQueue.Pending() > 0 {
+			state.liveryQueue.Run(ctx, state.config.concurrency(), queueSink)
+		}
+
+		// 关闭更新通道，等处理更新的 goroutine 退出后再写结果，以免旧的进度覆盖报告
+		close(statusUpdates)
+		close(progressUpdates)
+		<-drained
+	}()
+}
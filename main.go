@@ -1,60 +1,105 @@
 package main
 
 import (
-	"archive/zip"
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"io/fs"
-	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
+
+	"myapp/engine"
 )
 
-// Livery 结构体用于将涂装名称和下载链接关联起来。
+// Livery 结构体保存涂装目录中的一个条目。
 type Livery struct {
-	Name string
-	URL  string
+	ID                 string
+	Name               string
+	Airline            string
+	ICAO               string // 航空公司的三字 ICAO 代码，可以为空
+	Registration       string
+	Version            string // 目录中的涂装版本，可以为空
+	Source             string // 涂装所在的目录来源的名称
+	Real               bool
+	Author             string
+	URL                string
+	Size               int64
+	SHA256             string
+	Preview            string
+	MinAircraftVersion string
+}
+
+// pkg 返回安装引擎使用的涂装包。
+func (l Livery) pkg() engine.LiveryPackage {
+	return engine.LiveryPackage{ID: l.ID, Name: l.Name, Version: l.Version, URL: l.URL, Source: engine.Source{URL: l.URL, SHA256: l.SHA256, Size: l.Size}}
 }
 
 // AppState 保存应用程序的状态。
 type AppState struct {
 	app                 fyne.App // 将 App 实例保存在 state 中
+	config              *appConfig
 	xpPath              string
 	ag330Path           string
 	isAircraftInstalled bool
-	language            string
-	translations        map[string]string
-	mainWindow          fyne.Window
-	statusLabel         *widget.Label
-	progressBar         *widget.ProgressBar
-	installAircraftBtn  *widget.Button
-	updateExeBtn        *widget.Button
-	liveryCheckGroup    *widget.CheckGroup
-	installLiveryBtn    *widget.Button
-	updateListBtn       *widget.Button
-	uninstallBtn        *widget.Button
-	liveries            []Livery
+	// 飞机版本信息：已安装版本来自包内版本文件或安装收据，最新版本来自服务器
+	installedAircraftVersion string
+	latestAircraft           *engine.RemotePackageInfo
+	latestAircraftVersion    string // 版本清单公布的飞机包版本号，没有公布时为空
+	aircraftCheckDone        bool
+	aircraftUpdateAvailable  bool
+	installedVersionLabel    *widget.Label
+	latestVersionLabel       *widget.Label
+	updateStatusLabel        *widget.Label
+	appUpdate                *appRelease // 版本清单中比当前程序更新的版本，没有时为 nil
+	appUpdateBanner          *widget.Label
+	language                 string
+	translations             map[string]string
+	mainWindow               fyne.Window
+	statusLabel              *widget.Label
+	progressBar              *widget.ProgressBar
+	cancelBtn                *widget.Button
+	installAircraftBtn       *widget.Button
+	updateExeBtn             *widget.Button
+	catalog                  *catalogModel
+	catalogList              *widget.List
+	catalogGallery           *widget.GridWrap
+	reconcileLabel           *widget.Label // 对照涂装文件夹失败时显示在涂装数量旁边
+	liveryFilter             liveryFilter
+	liveryGallery            bool // 涂装页以画廊显示
+	previews                 *previewLoader
+	installLiveryBtn         *widget.Button
+	updateOutdatedBtn        *widget.Button
+	updateListBtn            *widget.Button
+	uninstallBtn             *widget.Button
+	liveries                 []Livery
+	liveryQueue              *engine.LiveryQueue
+	queueJobs                []engine.QueueJob // 队列列表显示的快照，队列变化时更新
+	queueList                *widget.List
+	queueRunning             atomic.Bool
+	queueProgressMu          sync.Mutex
+	queueProgress            map[string]engine.Event // 每个任务最近的进度或错误事件
+	// 正在进行的可取消操作共用一个 context，取消按钮会停止全部操作
+	opMu     sync.Mutex
+	opCount  int
+	opCtx    context.Context
+	opCancel context.CancelFunc
 }
 
 const (
-	LiveryListURL       = "https://files.zohopublic.com.cn/public/workdrive-public/download/kpgnr1efdca4ab9ed48a280b91151e177fa0c?x-cli-msg=%7B%22linkId%22%3A%221GNlXvxrDQf-36kFa%22%2C%22isFileOwner%22%3Afalse%2C%22version%22%3A%221.0%22%2C%22isWDSupport%22%3Afalse%7D"
-	ConcurrentDownloads = 4
+	LiveryListURL = "https://files.zohopublic.com.cn/public/workdrive-public/download/kpgnr1efdca4ab9ed48a280b91151e177fa0c?x-cli-msg=%7B%22linkId%22%3A%221GNlXvxrDQf-36kFa%22%2C%22isFileOwner%22%3Afalse%2C%22version%22%3A%221.0%22%2C%22isWDSupport%22%3Afalse%7D"
 )
 
-var downloadURLAg330 = []string{"https://files.zohopublic.com.cn/public/workdrive-public/download/dqd1m03114168cdbd47608183f4445c9b557c?x-cli-msg=%7B%22linkId%22%3A%221GNlXvxrBKN-36kFa%22%2C%22isFileOwner%22%3Afalse%2C%22version%22%3A%221.0%22%2C%22isWDSupport%22%3Afalse%7D"}
-var downloadURLUpdater = []string{"https://files.zohopublic.com.cn/public/workdrive-public/download/dqd1ma5b2ddd90a0647ed918d5ec5fe42de34?x-cli-msg=%7B%22linkId%22%3A%221GNlXvxrBKN-36kFa%22%2C%22isFileOwner%22%3Afalse%2C%22version%22%3A%221.0%22%2C%22isWDSupport%22%3Afalse%7D"}
+// 发布新包时同时更新 SHA256 和 Size，下载后会据此校验
+var downloadURLAg330 = []engine.Source{{URL: "https://files.zohopublic.com.cn/public/workdrive-public/download/dqd1m03114168cdbd47608183f4445c9b557c?x-cli-msg=%7B%22linkId%22%3A%221GNlXvxrBKN-36kFa%22%2C%22isFileOwner%22%3Afalse%2C%22version%22%3A%221.0%22%2C%22isWDSupport%22%3Afalse%7D"}}
+var downloadURLUpdater = []engine.Source{{URL: "https://files.zohopublic.com.cn/public/workdrive-public/download/dqd1ma5b2ddd90a0647ed918d5ec5fe42de34?x-cli-msg=%7B%22linkId%22%3A%221GNlXvxrBKN-36kFa%22%2C%22isFileOwner%22%3Afalse%2C%22version%22%3A%221.0%22%2C%22isWDSupport%22%3Afalse%7D"}}
 
 func (state *AppState) tr(key string, args ...interface{}) string {
 	format, ok := state.translations[key]
@@ -67,100 +112,101 @@ func (state *AppState) tr(key string, args ...interface{}) string {
 	return format
 }
 
-func getExecutablePath(filename string) (string, error) {
-	exePath, err := os.Executable()
-	if err != nil {
-		return "", err
+func main() {
+	// 第一个参数是命令时以命令行模式运行，不创建窗口
+	if code, ok := runCLI(os.Args[1:]); ok {
+		os.Exit(code)
 	}
-	return filepath.Join(filepath.Dir(exePath), filename), nil
-}
-
-func loadLiveriesFromFile() ([]Livery, error) {
-	path, err := getExecutablePath("LiveriesList.txt")
+	a := app.New()
+	w := a.NewWindow("AeroGennis A330-300 Installer")
+	w.Resize(fyne.NewSize(700, 500))
+	state := &AppState{app: a, mainWindow: w} // 在 state 中初始化 app
+	// 上次飞机安装中途退出时，先恢复到一致的状态
+	var recoverWarnings []engine.Event // 窗口显示后再提示，那时已经载入了界面语言
+	engine.RecoverStagedInstall(func(e engine.Event) { recoverWarnings = append(recoverWarnings, e) })
+	config, configWarnings, configErr := readConfig()
+	state.config = config
+	// 读取失败的来源被跳过，其它来源的涂装照常显示
+	liveries, err := loadCatalog(state.config)
+	state.liveries = liveries
 	if err != nil {
-		return nil, err
+		dialog.ShowError(err, w)
 	}
-	file, err := os.Open(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []Livery{}, nil
+	// 上次没有装完的涂装队列，用户可以在涂装页继续
+	var queueErr error
+	state.liveryQueue, queueErr = engine.LoadQueue()
+	state.liveryQueue.OnChange = func() { refreshQueueList(state) }
+	state.liveryQueue.Events = func(e engine.Event) {
+		if state.statusLabel != nil {
+			state.statusLabel.SetText(describeEvent(state, e))
 		}
-		return nil, fmt.Errorf("无法打开 LiveriesList.txt: %w", err)
 	}
-	defer file.Close()
-
-	var lines []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(line, `"`) && strings.HasSuffix(line, `",`) {
-			line = line[1 : len(line)-2]
-		} else if strings.HasPrefix(line, `"`) && strings.HasSuffix(line, `"`) {
-			line = line[1 : len(line)-1]
-		}
-		if line != "" {
-			lines = append(lines, line)
-		}
+	var previewErr error
+	state.previews, previewErr = newPreviewLoader()
+	showActiveProfile(state)
+	if configErr != nil {
+		dialog.ShowError(errors.New(configErrorText(state, configErr)), w)
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("读取 LiveriesList.txt 时出错: %w", err)
+	if len(configWarnings) > 0 {
+		showConfigWarnings(state, configWarnings)
 	}
-
-	var names, urls []string
-	for _, line := range lines {
-		if strings.HasPrefix(line, "http") {
-			urls = append(urls, line)
-		} else {
-			names = append(names, line)
-		}
+	if previewErr != nil {
+		dialog.ShowError(fmt.Errorf("%s: %w", state.tr("preview_cache_error"), previewErr), w)
 	}
-	if len(names) != len(urls) {
-		return nil, fmt.Errorf("涂装名称数量 (%d) 与下载链接数量 (%d) 不匹配", len(names), len(urls))
+	if queueErr != nil {
+		dialog.ShowError(fmt.Errorf("%s: %w", state.tr("queue_load_error"), queueErr), w)
 	}
-
-	var liveries []Livery
-	for i := 0; i < len(names); i++ {
-		liveries = append(liveries, Livery{Name: names[i], URL: urls[i]})
+	for _, e := range recoverWarnings {
+		dialog.ShowError(errors.New(describeEvent(state, e)), w)
 	}
-	return liveries, nil
+	// 后台检查是否有新版本，不阻塞界面
+	go checkAppUpdate(state)
+	// 由自更新启动时，通知旧进程并删除旧程序
+	if oldPath := engine.AfterUpdateOldPath(); oldPath != "" {
+		engine.FinishSelfUpdate(oldPath, AppVersion)
+	} else {
+		engine.RemoveOldExecutable()
+	}
+	w.ShowAndRun()
 }
 
-func main() {
-	a := app.New()
-	w := a.NewWindow("AeroGennis A330-300 Installer")
-	w.Resize(fyne.NewSize(700, 500))
-	state := &AppState{app: a, mainWindow: w} // 在 state 中初始化 app
-	loadedLiveries, err := loadLiveriesFromFile()
-	if err != nil {
-		dialog.ShowError(err, w)
-		state.liveries = []Livery{}
-	} else {
-		state.liveries = loadedLiveries
+// showConfigWarnings 列出读取配置时被忽略的设置。
+func showConfigWarnings(state *AppState, warnings []configWarning) {
+	lines := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		lines = append(lines, "- "+state.tr(w.key, w.args...))
 	}
-	xpPath, lang, ag330Path := readConfig()
-	if lang == "" {
+	dialog.ShowInformation(state.tr("config_warnings_title"), strings.Join(lines, "\n"), state.mainWindow)
+}
+
+// showActiveProfile 载入当前配置档的路径和语言，并显示对应的界面。
+func showActiveProfile(state *AppState) {
+	w := state.mainWindow
+	p := state.config.activeProfile()
+	state.xpPath, state.language, state.ag330Path = p.XPlanePath, p.Language, p.AG330Path
+	state.isAircraftInstalled = false
+	state.installedAircraftVersion = ""
+	state.aircraftCheckDone = false
+	state.aircraftUpdateAvailable = false
+	if state.language == "" {
 		w.SetContent(createLanguageSelectionUI(state))
-	} else {
-		state.language = lang
-		loadTranslations(state)
-		w.SetTitle(state.tr("window_title"))
-		if xpPath != "" {
-			if valid, _ := validateXPlaneDirectory(xpPath); valid {
-				state.xpPath = xpPath
-				state.ag330Path = ag330Path
-				checkAircraftInstallation(state)
-			} else {
-				state.xpPath = ""
-				writeConfig(state)
-			}
-		}
-		if state.xpPath == "" {
-			w.SetContent(createSetupUI(state))
+		return
+	}
+	loadTranslations(state)
+	w.SetTitle(state.tr("window_title", AppVersion))
+	if state.xpPath != "" {
+		if valid, _ := engine.ValidateXPlaneDir(state.xpPath); valid {
+			checkAircraftInstallation(state)
 		} else {
-			w.SetContent(createMainUI(state))
+			state.xpPath = ""
+			writeConfig(state)
 		}
 	}
-	w.ShowAndRun()
+	if state.xpPath == "" {
+		w.SetContent(createSetupUI(state))
+	} else {
+		w.SetContent(createMainUI(state))
+	}
 }
 
 func createLanguageSelectionUI(state *AppState) fyne.CanvasObject {
@@ -179,7 +225,7 @@ func createLanguageSelectionUI(state *AppState) fyne.CanvasObject {
 			dialog.ShowError(fmt.Errorf("%s: %w", state.tr("save_config_error"), err), state.mainWindow)
 			return
 		}
-		state.mainWindow.SetTitle(state.tr("window_title"))
+		state.mainWindow.SetTitle(state.tr("window_title", AppVersion))
 		state.mainWindow.SetContent(createSetupUI(state))
 	})
 	return container.NewVBox(title, prompt, langSelect, continueBtn)
@@ -198,7 +244,7 @@ func createSetupUI(state *AppState) fyne.CanvasObject {
 	})
 	saveBtn := widget.NewButton(state.tr("save_continue_button"), func() {
 		path := pathEntry.Text
-		if valid, missing := validateXPlaneDirectory(path); !valid {
+		if valid, missing := engine.ValidateXPlaneDir(path); !valid {
 			msg := fmt.Sprintf("%s\n%s\n- %s", state.tr("invalid_xp_path_error"), state.tr("missing_items_label"), strings.Join(missing, "\n- "))
 			dialog.ShowError(fmt.Errorf("%s", msg), state.mainWindow)
 			return
@@ -211,12 +257,35 @@ func createSetupUI(state *AppState) fyne.CanvasObject {
 		checkAircraftInstallation(state)
 		state.mainWindow.SetContent(createMainUI(state))
 	})
-	return container.NewVBox(widget.NewLabel(state.tr("setup_welcome")), pathEntry, browseBtn, saveBtn)
+	// 自动查找已安装的 X-Plane 12，选中后填入路径
+	discovered := engine.DiscoverXPlaneInstalls()
+	var discoveredBox fyne.CanvasObject
+	if len(discovered) == 0 {
+		discoveredBox = widget.NewLabel(state.tr("discovered_none_label"))
+	} else {
+		discoveredList := widget.NewRadioGroup(discovered, func(selected string) {
+			if selected != "" {
+				pathEntry.SetText(selected)
+			}
+		})
+		discoveredList.SetSelected(discovered[0])
+		discoveredBox = container.NewVBox(widget.NewLabel(state.tr("discovered_installs_label")), discoveredList)
+	}
+	return container.NewVBox(widget.NewLabel(state.tr("setup_welcome")), discoveredBox, pathEntry, browseBtn, saveBtn)
 }
 
 func createMainUI(state *AppState) fyne.CanvasObject {
 	state.statusLabel = widget.NewLabel(state.tr("status_ready"))
+	if pending := state.liveryQueue.Pending(); pending > 0 && !state.queueRunning.Load() {
+		state.statusLabel.SetText(state.tr("queue_pending_status", pending))
+	}
 	state.progressBar = widget.NewProgressBar()
+	state.cancelBtn = widget.NewButton(state.tr("cancel_button"), func() { handleCancel(state) })
+	state.opMu.Lock()
+	if state.opCount == 0 {
+		state.cancelBtn.Disable()
+	}
+	state.opMu.Unlock()
 	tabs := container.NewAppTabs(
 		container.NewTabItem(state.tr("tab_aircraft"), createAircraftTab(state)),
 		container.NewTabItem(state.tr("tab_liveries"), createLiveryTab(state)),
@@ -224,49 +293,88 @@ func createMainUI(state *AppState) fyne.CanvasObject {
 		container.NewTabItem(state.tr("tab_settings"), createSettingsTab(state)),
 	)
 	tabs.SetTabLocation(container.TabLocationTop)
-	return container.NewBorder(nil, container.NewVBox(state.statusLabel, state.progressBar), nil, nil, tabs)
+	progressRow := container.NewBorder(nil, nil, nil, state.cancelBtn, state.progressBar)
+	return container.NewBorder(nil, container.NewVBox(state.statusLabel, progressRow), nil, nil, tabs)
+}
+
+// startOperation 开始一个可以取消的后台操作并启用取消按钮；操作结束时必须调用 finishOperation。
+func startOperation(state *AppState) context.Context {
+	state.opMu.Lock()
+	defer state.opMu.Unlock()
+	if state.opCount == 0 {
+		state.opCtx, state.opCancel = context.WithCancel(context.Background())
+	}
+	state.opCount++
+	state.cancelBtn.Enable()
+	return state.opCtx
+}
+
+func finishOperation(state *AppState) {
+	state.opMu.Lock()
+	defer state.opMu.Unlock()
+	state.opCount--
+	if state.opCount == 0 {
+		state.opCancel()
+		state.cancelBtn.Disable()
+	}
+}
+
+// handleCancel 取消正在进行的下载和安装，引擎会删除临时文件和解压了一半的目录。
+func handleCancel(state *AppState) {
+	state.opMu.Lock()
+	defer state.opMu.Unlock()
+	if state.opCount == 0 {
+		return
+	}
+	state.opCancel()
+	state.cancelBtn.Disable()
+	state.statusLabel.SetText(state.tr("status_cancelling"))
 }
 
 func createAircraftTab(state *AppState) fyne.CanvasObject {
 	var content fyne.CanvasObject
+	state.installedVersionLabel = widget.NewLabel("")
+	state.latestVersionLabel = widget.NewLabel("")
+	state.updateStatusLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
 	if state.isAircraftInstalled {
 		state.installAircraftBtn = widget.NewButton(state.tr("reinstall_button"), func() { handleAircraftInstall(state) })
-		content = container.NewVBox(widget.NewLabelWithStyle(state.tr("aircraft_installed_title"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}), widget.NewLabel(state.tr("aircraft_installed_desc")), state.installAircraftBtn)
+		verifyBtn := widget.NewButton(state.tr("verify_aircraft_button"), func() { handleVerifyAircraft(state) })
+		content = container.NewVBox(widget.NewLabelWithStyle(state.tr("aircraft_installed_title"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}), widget.NewLabel(state.tr("aircraft_installed_desc")), state.installedVersionLabel, state.latestVersionLabel, state.updateStatusLabel, state.installAircraftBtn, verifyBtn)
 	} else {
 		state.installAircraftBtn = widget.NewButton(state.tr("install_aircraft_button"), func() { handleAircraftInstall(state) })
-		content = container.NewVBox(widget.NewLabelWithStyle(state.tr("aircraft_tab_title"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}), widget.NewLabel(state.tr("aircraft_tab_desc")), state.installAircraftBtn)
+		content = container.NewVBox(widget.NewLabelWithStyle(state.tr("aircraft_tab_title"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}), widget.NewLabel(state.tr("aircraft_tab_desc")), state.latestVersionLabel, state.installAircraftBtn)
+	}
+	refreshAircraftVersionLabels(state)
+	if !state.aircraftCheckDone {
+		go checkAircraftUpdate(state)
 	}
 	return content
 }
 
 func createLiveryTab(state *AppState) fyne.CanvasObject {
-	var liveryNames []string
-	for _, livery := range state.liveries {
-		liveryNames = append(liveryNames, livery.Name)
-	}
-	state.liveryCheckGroup = widget.NewCheckGroup(liveryNames, func(selected []string) {
-		if len(selected) > 0 {
-			state.installLiveryBtn.Enable()
-			state.statusLabel.SetText(state.tr("liveries_selected_status", len(selected)))
-		} else {
-			state.installLiveryBtn.Disable()
-			state.statusLabel.SetText(state.tr("no_livery_selected_status"))
-		}
-	})
 	state.installLiveryBtn = widget.NewButton(state.tr("install_selected_liveries_button"), func() { handleBatchLiveryInstall(state) })
 	state.installLiveryBtn.Disable()
 	state.updateListBtn = widget.NewButton(state.tr("update_livery_list_button"), func() { handleUpdateLiveryList(state) })
 	state.uninstallBtn = widget.NewButton(state.tr("uninstall_liveries_button"), func() { handleUninstallLiveries(state) })
-	bottomBar := container.NewVBox(state.installLiveryBtn, container.NewGridWithColumns(2, state.updateListBtn, state.uninstallBtn))
+	state.updateOutdatedBtn = widget.NewButton("", func() { handleUpdateOutdatedLiveries(state) })
+	refreshedLabel := widget.NewLabelWithStyle(catalogRefreshedText(state), fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+	bottomBar := container.NewVBox(container.NewGridWithColumns(2, state.installLiveryBtn, state.updateOutdatedBtn), container.NewGridWithColumns(2, state.updateListBtn, state.uninstallBtn), refreshedLabel)
 	if len(state.liveries) == 0 {
 		return container.NewCenter(container.NewVBox(widget.NewLabel(state.tr("livery_list_load_fail")), state.updateListBtn))
 	}
-	return container.NewBorder(nil, bottomBar, nil, nil, container.NewScroll(state.liveryCheckGroup))
+	split := container.NewVSplit(createLiveryBrowser(state), createQueuePanel(state))
+	refreshUpdateOutdatedButton(state)
+	split.Offset = 0.6
+	return container.NewBorder(nil, bottomBar, nil, nil, split)
 }
 
 func createUpdateTab(state *AppState) fyne.CanvasObject {
 	state.updateExeBtn = widget.NewButton(state.tr("download_latest_button"), func() { handleExeUpdate(state) })
-	return container.NewVBox(widget.NewLabelWithStyle(state.tr("update_tab_title"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}), widget.NewLabel(state.tr("update_tab_desc")), widget.NewLabel(state.tr("update_tab_warning")), state.updateExeBtn)
+	state.appUpdateBanner = widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	state.appUpdateBanner.Importance = widget.HighImportance
+	state.appUpdateBanner.Wrapping = fyne.TextWrapWord
+	refreshAppUpdateBanner(state)
+	return container.NewVBox(widget.NewLabelWithStyle(state.tr("update_tab_title"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}), state.appUpdateBanner, widget.NewLabel(state.tr("current_app_version_label", AppVersion)), widget.NewLabel(state.tr("update_tab_desc")), widget.NewLabel(state.tr("update_tab_warning")), state.updateExeBtn)
 }
 
 func createSettingsTab(state *AppState) fyne.CanvasObject {
@@ -301,130 +409,19 @@ func createSettingsTab(state *AppState) fyne.CanvasObject {
 	selfUninstallWarning.Wrapping = fyne.TextWrapWord
 	selfUninstallBtn := widget.NewButton(state.tr("self_uninstall_button"), func() { handleSelfUninstall(state) })
 	selfUninstallBtn.Importance = widget.DangerImportance
-	return container.NewVBox(
+	// 设置项较多，放在可滚动的容器中
+	return container.NewVScroll(container.NewVBox(
 		widget.NewLabelWithStyle(state.tr("settings_tab_title"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		createProfileSection(state), widget.NewSeparator(),
+		createDownloadSection(state), widget.NewSeparator(),
+		createCatalogSourcesSection(state), widget.NewSeparator(),
 		pathLabel, changePathBtn, changeLangBtn, widget.NewSeparator(),
 		widget.NewLabelWithStyle(state.tr("manual_path_label"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		ag330PathEntry, saveAg330PathBtn, widget.NewSeparator(),
 		widget.NewLabelWithStyle(state.tr("danger_zone_label"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
 		uninstallAircraftBtn, widget.NewSeparator(),
 		selfUninstallWarning, selfUninstallBtn,
-	)
-}
-
-func downloadFileWithProgress(url, destPath string, state *AppState) error {
-	// URL 验证
-	if !strings.HasPrefix(url, "https://files.zohopublic.com.cn") {
-		return fmt.Errorf("无效的下载 URL: %s", url)
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return err
-	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
-	totalBytes := resp.ContentLength
-	file, err := os.Create(destPath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// 使用 bufio.Writer 优化写入性能
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
-
-	if totalBytes <= 0 {
-		state.statusLabel.SetText(state.tr("download_no_progress"))
-		state.progressBar.SetValue(0.5) // Indicate activity
-		_, err = io.Copy(writer, resp.Body)
-		return err
-	}
-
-	// 优化缓冲区大小为 64KB
-	buf := make([]byte, 64*1024)
-	var downloadedBytes int64
-	startTime := time.Now()
-	for {
-		n, readErr := resp.Body.Read(buf)
-		if n > 0 {
-			if _, writeErr := writer.Write(buf[0:n]); writeErr != nil {
-				return writeErr
-			}
-			downloadedBytes += int64(n)
-			speed := float64(downloadedBytes) / time.Since(startTime).Seconds() / (1024 * 1024)
-
-			// 计算进度百分比 (0.0 到 1.0)
-			progress := float64(downloadedBytes) / float64(totalBytes)
-			state.statusLabel.SetText(state.tr("download_progress_label", float64(downloadedBytes)/(1024*1024), float64(totalBytes)/(1024*1024), speed))
-			state.progressBar.SetValue(progress)
-		}
-		if readErr == io.EOF {
-			break
-		}
-		if readErr != nil {
-			return readErr
-		}
-	}
-	return nil
-}
-
-func extractZipGUI(zipFile, destDir string, isAircraft bool, state *AppState) error {
-	r, err := zip.OpenReader(zipFile)
-	if err != nil {
-		return err
-	}
-	defer r.Close()
-
-	totalFiles := len(r.File)
-	destRoot := destDir
-	if isAircraft {
-		destRoot = filepath.Join(destDir, "AeroGennis Airbus A330-300")
-	}
-
-	for i, f := range r.File {
-		fpath := filepath.Join(destRoot, f.Name)
-		// 路径安全验证
-		if !strings.HasPrefix(filepath.Clean(fpath), filepath.Clean(destRoot)+string(os.PathSeparator)) {
-			return fmt.Errorf("非法文件路径: %s", fpath)
-		}
-
-		// 计算进度百分比 (0.0 到 1.0)
-		progress := float64(i+1) / float64(totalFiles)
-		state.progressBar.SetValue(progress)
-		state.statusLabel.SetText(state.tr("extract_progress_label", f.Name))
-
-		if f.FileInfo().IsDir() {
-			os.MkdirAll(fpath, os.ModePerm)
-			continue
-		}
-		if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
-			return err
-		}
-		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			return err
-		}
-		rc, err := f.Open()
-		if err != nil {
-			outFile.Close()
-			return err
-		}
-		_, err = io.Copy(outFile, rc)
-		outFile.Close()
-		rc.Close()
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	))
 }
 
 func handleUninstallLiveries(state *AppState) {
@@ -433,29 +430,44 @@ func handleUninstallLiveries(state *AppState) {
 		return
 	}
 	liveriesPath := filepath.Join(state.ag330Path, "liveries")
-	entries, err := os.ReadDir(liveriesPath)
+	installedLiveryNames, err := engine.InstalledLiveryDirs(liveriesPath)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("%s: %w", state.tr("scan_liveries_dir_error"), err), state.mainWindow)
 		return
 	}
-	var installedLiveryNames []string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			installedLiveryNames = append(installedLiveryNames, entry.Name())
-		}
-	}
 	if len(installedLiveryNames) == 0 {
 		dialog.ShowInformation(state.tr("no_installed_liveries_title"), state.tr("no_installed_liveries_message"), state.mainWindow)
 		return
 	}
-	checkGroup := widget.NewCheckGroup(installedLiveryNames, nil)
+	// 属于目录涂装的文件夹后面注明涂装名称，选项文字再对应回文件夹名
+	owners := make(map[string]string)
+	installs, _ := liveryInstalls(state) // 对照失败时涂装页的数量旁已经显示了错误
+	for _, l := range state.liveries {
+		for _, dir := range installs[l.ID].Dirs {
+			owners[dir] = l.Name
+		}
+	}
+	dirByLabel := make(map[string]string)
+	var labels []string
+	for _, dir := range installedLiveryNames {
+		label := dir
+		if name, ok := owners[dir]; ok && !strings.EqualFold(name, dir) {
+			label = state.tr("uninstall_livery_entry", dir, name)
+		}
+		dirByLabel[label] = dir
+		labels = append(labels, label)
+	}
+	checkGroup := widget.NewCheckGroup(labels, nil)
 	uninstallDialog := dialog.NewCustomConfirm(
 		state.tr("uninstall_dialog_title"), state.tr("confirm_button"), state.tr("cancel_button"), container.NewScroll(checkGroup),
 		func(confirm bool) {
 			if !confirm {
 				return
 			}
-			selectedToUninstall := checkGroup.Selected
+			var selectedToUninstall []string
+			for _, label := range checkGroup.Selected {
+				selectedToUninstall = append(selectedToUninstall, dirByLabel[label])
+			}
 			if len(selectedToUninstall) == 0 {
 				return
 			}
@@ -464,20 +476,11 @@ func handleUninstallLiveries(state *AppState) {
 				if !finalConfirm {
 					return
 				}
-				var deletedCount, errorCount int
-				var errorMessages []string
-				for _, name := range selectedToUninstall {
-					pathToDelete := filepath.Join(liveriesPath, name)
-					if err := os.RemoveAll(pathToDelete); err != nil {
-						errorCount++
-						errorMessages = append(errorMessages, fmt.Sprintf("%s: %v", name, err))
-					} else {
-						deletedCount++
-					}
-				}
+				deletedCount, errorMessages := engine.UninstallLiveryDirs(liveriesPath, selectedToUninstall)
+				refreshLiveryStates(state)
 				resultMsg := state.tr("uninstall_report_message", deletedCount)
-				if errorCount > 0 {
-					resultMsg += "\n" + state.tr("uninstall_report_errors", errorCount, strings.Join(errorMessages, "\n"))
+				if len(errorMessages) > 0 {
+					resultMsg += "\n" + state.tr("uninstall_report_errors", len(errorMessages), strings.Join(errorMessages, "\n"))
 				}
 				dialog.ShowInformation(state.tr("uninstall_complete_title"), resultMsg, state.mainWindow)
 			}, state.mainWindow)
@@ -486,39 +489,67 @@ func handleUninstallLiveries(state *AppState) {
 	uninstallDialog.Show()
 }
 
+// handleUpdateOutdatedLiveries 把当前飞机上所有过期的涂装加入队列，重新安装到它们所在的涂装文件夹。
+func handleUpdateOutdatedLiveries(state *AppState) {
+	outdated := state.catalog.outdatedLiveries()
+	if len(outdated) == 0 {
+		dialog.ShowInformation(state.tr("update_outdated_title"), state.tr("no_outdated_liveries_message"), state.mainWindow)
+		return
+	}
+	if !state.isAircraftInstalled || state.ag330Path == "" {
+		dialog.ShowError(fmt.Errorf("%s", state.tr("find_aircraft_dir_error")), state.mainWindow)
+		return
+	}
+	var names []string
+	for _, l := range outdated {
+		names = append(names, l.Name)
+	}
+	msg := state.tr("update_outdated_confirm_message", len(outdated), strings.Join(names, "\n- "))
+	dialog.ShowConfirm(state.tr("update_outdated_title"), msg, func(confirm bool) {
+		if !confirm {
+			return
+		}
+		withCompatibleLiveries(state, outdated, func(outdated []Livery) {
+			liveryDirs := []string{filepath.Join(state.ag330Path, "liveries")}
+			for _, l := range outdated {
+				state.liveryQueue.Add(l.pkg(), liveryDirs)
+			}
+			state.updateOutdatedBtn.Disable()
+			runLiveryQueue(state)
+		})
+	}, state.mainWindow)
+}
+
 func handleExeUpdate(state *AppState) {
-	dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
-		if err != nil || uri == nil {
+	msg, unverified := updateConfirmMessage(state)
+	dialog.ShowConfirm(state.tr("update_confirm_title"), msg, func(confirm bool) {
+		if !confirm {
 			return
 		}
-		savePath := uri.Path()
 		state.updateExeBtn.Disable()
-		state.progressBar.SetValue(0)
 
+		ctx := startOperation(state)
 		go func() {
 			defer func() {
+				finishOperation(state)
 				state.updateExeBtn.Enable()
 			}()
 
-			exeName := "AeroGennis_Updater_New.exe"
-			exePath := filepath.Join(savePath, exeName)
-			state.statusLabel.SetText(state.tr("status_downloading_update"))
-
-			err := downloadFileWithProgress(downloadURLUpdater[0], exePath, state)
+			err := selfUpdate(ctx, state, guiReporter{state}, unverified)
+			if isCancelled(err) {
+				state.statusLabel.SetText(state.tr("operation_cancelled_status"))
+				return
+			}
 			if err != nil {
-				state.statusLabel.SetText(state.tr("download_failed_status"))
-				dialog.ShowError(fmt.Errorf("%s: %w", state.tr("download_update_error"), err), state.mainWindow)
+				dialog.ShowError(err, state.mainWindow)
 				return
 			}
-			state.statusLabel.SetText(state.tr("update_download_complete_status"))
-			dialog.ShowInformation(state.tr("update_download_complete_title"), state.tr("update_download_complete_message", exePath), state.mainWindow)
+			// 新版本已经启动，退出旧版本
+			state.app.Quit()
 		}()
 	}, state.mainWindow)
 }
 
-// (removed duplicate definition of downloadFileWithProgressSafe)
-
-// (Removed duplicate definition of extractZipGUISafe)
 func handleUninstallAircraft(state *AppState) {
 	if !state.isAircraftInstalled || state.ag330Path == "" {
 		return
@@ -537,15 +568,11 @@ func handleUninstallAircraft(state *AppState) {
 					if !finalConfirm {
 						return
 					}
-					err := os.RemoveAll(state.ag330Path)
-					if err != nil {
+					if err := uninstallAircraft(state); err != nil {
 						dialog.ShowError(fmt.Errorf("%s: %w", state.tr("uninstall_aircraft_error"), err), state.mainWindow)
 						return
 					}
 					dialog.ShowInformation(state.tr("uninstall_complete_title"), state.tr("uninstall_aircraft_success_message"), state.mainWindow)
-					state.isAircraftInstalled = false
-					state.ag330Path = ""
-					writeConfig(state)
 					state.mainWindow.SetContent(createMainUI(state))
 				},
 				state.mainWindow,
@@ -568,29 +595,21 @@ func handleSelfUninstall(state *AppState) {
 				dialog.ShowError(err, state.mainWindow)
 				return
 			}
-			confPath, _ := getExecutablePath("Ag330UpdaterConf.txt")
-			listPath, _ := getExecutablePath("LiveriesList.txt")
-			scriptContent := fmt.Sprintf(
-				`@echo off
-timeout /t 2 /nobreak > NUL
-del "%s"
-del "%s"
-del "%s"
-(goto) 2>nul & del "%%~f0"
-`, confPath, listPath, exePath)
-			tempBatFile, err := os.CreateTemp("", "uninstall_*.bat")
-			if err != nil {
-				dialog.ShowError(err, state.mainWindow)
-				return
+			// 程序目录下由本程序生成的文件
+			var dataFiles []string
+			for _, name := range []string{configFileName, legacyConfigFileName, legacyCatalogFileName, catalogFileName, engine.ReceiptsFileName, engine.SwapJournalFileName, engine.QueueFileName,
+				catalogStateFileName, catalogFileName + catalogBackupSuffix, legacyCatalogFileName + catalogBackupSuffix} {
+				if p, err := engine.DataPath(name); err == nil {
+					dataFiles = append(dataFiles, p)
+				}
 			}
-			if _, err := tempBatFile.WriteString(scriptContent); err != nil {
-				tempBatFile.Close()
-				dialog.ShowError(err, state.mainWindow)
-				return
+			var dataDirs []string
+			for _, name := range []string{"downloads", engine.PreviewCacheDirName, catalogCacheDirName} {
+				if p, err := engine.DataPath(name); err == nil {
+					dataDirs = append(dataDirs, p)
+				}
 			}
-			tempBatFile.Close()
-			cmd := exec.Command("cmd", "/C", "start", "/b", tempBatFile.Name())
-			if err := cmd.Start(); err != nil {
+			if err := engine.StartSelfUninstall(exePath, dataFiles, dataDirs); err != nil {
 				dialog.ShowError(err, state.mainWindow)
 				return
 			}
@@ -601,458 +620,182 @@ del "%s"
 }
 
 func handleAircraftInstall(state *AppState) {
+	chooseProfileTargets(state, aircraftTargetProfiles(state), func(profiles []string) { installAircraft(state, profiles) })
+}
+
+// installAircraft 下载一次飞机包，然后依次安装到所选的每个配置档。
+func installAircraft(state *AppState, profiles []string) {
 	state.installAircraftBtn.Disable()
+	ctx := startOperation(state)
 
 	go func() {
 		defer func() {
+			finishOperation(state)
 			state.installAircraftBtn.Enable()
 		}()
 
-		// 更新状态标签
-		state.statusLabel.SetText(state.tr("status_creating_temp_dir"))
-		state.progressBar.SetValue(0)
-
-		tmpDir, err := os.MkdirTemp("", "xplane_aircraft_*")
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("%s: %w", state.tr("temp_dir_error"), err), state.mainWindow)
+		results, err := installAircraftToProfiles(ctx, state, profiles, guiReporter{state})
+		if isCancelled(err) {
+			state.aircraftCheckDone = false
+			state.mainWindow.SetContent(createMainUI(state))
+			state.statusLabel.SetText(state.tr("operation_cancelled_status"))
 			return
 		}
-		defer os.RemoveAll(tmpDir)
-
-		zipPath := filepath.Join(tmpDir, "aircraft.zip")
-		state.statusLabel.SetText(state.tr("status_downloading", state.tr("aircraft_package")))
-
-		err = downloadFileWithProgress(downloadURLAg330[0], zipPath, state)
 		if err != nil {
-			state.statusLabel.SetText(state.tr("download_failed_status"))
-			dialog.ShowError(fmt.Errorf("%s: %w", state.tr("download_error", state.tr("aircraft_package")), err), state.mainWindow)
+			dialog.ShowError(err, state.mainWindow)
 			return
 		}
-
-		aircraftDir := filepath.Join(state.xpPath, "Aircraft", "Laminar Research")
-		state.statusLabel.SetText(state.tr("status_extracting", aircraftDir))
-
-		err = extractZipGUI(zipPath, aircraftDir, true, state)
-		if err != nil {
-			state.statusLabel.SetText(state.tr("extraction_failed_status"))
-			dialog.ShowError(fmt.Errorf("%s: %w", state.tr("extraction_error", state.tr("aircraft_package")), err), state.mainWindow)
+		state.aircraftCheckDone = false // 重新与服务器比较
+		state.mainWindow.SetContent(createMainUI(state))
+		if failures := installFailures(results); len(failures) > 0 {
+			state.statusLabel.SetText(state.tr("install_failed_status"))
+			dialog.ShowError(errors.Join(failures...), state.mainWindow)
 			return
 		}
-
-		checkAircraftInstallation(state)
-		state.mainWindow.SetContent(createMainUI(state))
 		state.statusLabel.SetText(state.tr("install_complete_status"))
-		dialog.ShowInformation(state.tr("install_success_title"), state.tr("aircraft_install_success_message"), state.mainWindow)
+		message := state.tr("aircraft_install_success_message")
+		if len(results) > 0 {
+			if warning := unverifiedWarning(state, results[0]); warning != "" {
+				message += "\n\n" + warning
+			}
+		}
+		dialog.ShowInformation(state.tr("install_success_title"), message, state.mainWindow)
 	}()
 }
 
-func handleUpdateLiveryList(state *AppState) {
-	state.updateListBtn.Disable()
-	state.statusLabel.SetText(state.tr("status_updating_livery_list"))
+func handleVerifyAircraft(state *AppState) {
+	state.installAircraftBtn.Disable()
+	ctx := startOperation(state)
 	go func() {
-		defer state.updateListBtn.Enable()
-		resp, err := http.Get(LiveryListURL)
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("%s: %w", state.tr("livery_list_download_error"), err), state.mainWindow)
-			return
-		}
-		defer resp.Body.Close()
-		data, err := io.ReadAll(resp.Body)
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("%s: %w", state.tr("livery_list_read_error"), err), state.mainWindow)
+		defer func() {
+			finishOperation(state)
+			state.installAircraftBtn.Enable()
+		}()
+		receipt, problems, err := verifyAircraftInstall(ctx, state, guiReporter{state})
+		if isCancelled(err) {
+			state.statusLabel.SetText(state.tr("operation_cancelled_status"))
 			return
 		}
-		path, err := getExecutablePath("LiveriesList.txt")
 		if err != nil {
 			dialog.ShowError(err, state.mainWindow)
 			return
 		}
-		if err := os.WriteFile(path, data, 0644); err != nil {
-			dialog.ShowError(fmt.Errorf("%s: %w", state.tr("livery_list_save_error"), err), state.mainWindow)
+		if receipt == nil {
+			dialog.ShowInformation(state.tr("verify_title"), state.tr("verify_no_receipt_message"), state.mainWindow)
 			return
 		}
-		loadedLiveries, err := loadLiveriesFromFile()
-		if err != nil {
-			dialog.ShowError(err, state.mainWindow)
-		} else {
-			state.liveries = loadedLiveries
-			state.mainWindow.SetContent(createMainUI(state))
-			dialog.ShowInformation(state.tr("update_success_title"), state.tr("livery_list_update_success"), state.mainWindow)
-		}
-
-	}()
-}
-
-func handleBatchLiveryInstall(state *AppState) {
-	selectedNames := state.liveryCheckGroup.Selected
-	if len(selectedNames) == 0 {
-		dialog.ShowInformation(state.tr("no_livery_selected_title"), state.tr("no_livery_selected_message"), state.mainWindow)
-		return
-	}
-	if !state.isAircraftInstalled || state.ag330Path == "" {
-		dialog.ShowError(fmt.Errorf("%s", state.tr("find_aircraft_dir_error")), state.mainWindow)
-		return
-	}
-
-	// 去重处理
-	uniqueNames := make(map[string]struct{})
-	var downloadQueue []Livery
-	for _, name := range selectedNames {
-		if _, exists := uniqueNames[name]; exists {
-			continue // 跳过重复的涂装
+		state.statusLabel.SetText(state.tr("status_ready"))
+		if len(problems) == 0 {
+			dialog.ShowInformation(state.tr("verify_title"), state.tr("verify_ok_message", len(receipt.Files)), state.mainWindow)
+			return
 		}
-		uniqueNames[name] = struct{}{}
-		for _, livery := range state.liveries {
-			if livery.Name == name {
-				downloadQueue = append(downloadQueue, livery)
+		const maxListed = 20
+		var lines []string
+		for i, p := range problems {
+			if i == maxListed {
+				lines = append(lines, "...")
 				break
 			}
+			if p.Missing {
+				lines = append(lines, state.tr("verify_missing_file", p.Path))
+			} else {
+				lines = append(lines, state.tr("verify_changed_file", p.Path))
+			}
 		}
-	}
+		dialog.ShowInformation(state.tr("verify_title"), state.tr("verify_problems_message", len(problems), strings.Join(lines, "\n")), state.mainWindow)
+	}()
+}
 
-	state.installLiveryBtn.Disable()
+func handleUpdateLiveryList(state *AppState) {
 	state.updateListBtn.Disable()
-	state.uninstallBtn.Disable()
-
+	ctx := startOperation(state)
 	go func() {
-		totalJobs := len(downloadQueue)
 		defer func() {
-			// 使用 RunOnMain 确保 UI 更新在主线程中执行
-			state.mainWindow.Canvas().Refresh(state.mainWindow.Content())
-			state.installLiveryBtn.Enable()
+			finishOperation(state)
 			state.updateListBtn.Enable()
-			state.uninstallBtn.Enable()
-			state.liveryCheckGroup.SetSelected([]string{})
-			state.statusLabel.SetText(state.tr("batch_install_complete_status", totalJobs))
-			dialog.ShowInformation(state.tr("install_success_title"), state.tr("batch_install_complete_message", totalJobs), state.mainWindow)
 		}()
-
-		var wg sync.WaitGroup
-		var completedCount atomic.Int32
-		jobs := make(chan Livery, totalJobs)
-
-		// 创建一个通道来传递状态更新
-		statusUpdates := make(chan string, 100)
-		progressUpdates := make(chan float64, 100)
-
-		// 启动一个 goroutine 来处理所有 UI 更新
-		go func() {
-			for {
-				select {
-				case status, ok := <-statusUpdates:
-					if !ok {
-						return
-					}
-					state.statusLabel.SetText(status)
-				case progress, ok := <-progressUpdates:
-					if !ok {
-						return
-					}
-					state.progressBar.SetValue(progress)
-				}
-			}
-		}()
-
-		for i := 1; i <= ConcurrentDownloads; i++ {
-			wg.Add(1)
-			go liveryInstallWorker(i, state, jobs, &wg, &completedCount, totalJobs, statusUpdates, progressUpdates)
-		}
-
-		for _, livery := range downloadQueue {
-			jobs <- livery
-		}
-		close(jobs)
-		wg.Wait()
-
-		// 关闭更新通道
-		close(statusUpdates)
-		close(progressUpdates)
-	}()
-}
-
-func liveryInstallWorker(id int, state *AppState, jobs <-chan Livery, wg *sync.WaitGroup, counter *atomic.Int32, total int, statusUpdates chan<- string, progressUpdates chan<- float64) {
-	defer wg.Done()
-	for livery := range jobs {
-		currentNum := counter.Add(1)
-
-		// 发送状态更新到通道而不是直接更新 UI
-		select {
-		case statusUpdates <- state.tr("batch_download_progress_label", currentNum, total, livery.Name):
-		default:
-		}
-		select {
-		case progressUpdates <- 0:
-		default:
-		}
-
-		liveryDir := filepath.Join(state.ag330Path, "liveries")
-		os.MkdirAll(liveryDir, 0755)
-		tmpDir, err := os.MkdirTemp("", "xplane_livery_*")
-		if err != nil {
-			fmt.Printf("Worker %d: 创建临时目录失败: %v\n", id, err)
-			continue
-		}
-		zipPath := filepath.Join(tmpDir, "livery.zip")
-
-		// 创建一个包装的 state 来安全地更新进度
-		wrappedState := &AppState{
-			app:          state.app,
-			statusLabel:  state.statusLabel,
-			progressBar:  state.progressBar,
-			translations: state.translations,
-			language:     state.language,
-		}
-
-		err = downloadFileWithProgressSafe(livery.URL, zipPath, wrappedState, statusUpdates, progressUpdates)
-		if err != nil {
-			fmt.Printf("Worker %d: 下载 '%s' 失败: %v\n", id, livery.Name, err)
-			os.RemoveAll(tmpDir)
-			continue
+		refresh, err := updateLiveryList(ctx, state, guiReporter{state})
+		if isCancelled(err) {
+			state.statusLabel.SetText(state.tr("operation_cancelled_status"))
+			return
 		}
-
-		err = extractZipGUISafe(zipPath, liveryDir, false, wrappedState, statusUpdates, progressUpdates)
 		if err != nil {
-			fmt.Printf("Worker %d: 解压 '%s' 失败: %v\n", id, livery.Name, err)
+			dialog.ShowError(err, state.mainWindow)
+			return
 		}
-		os.RemoveAll(tmpDir)
-	}
+		state.liveries = refresh.Liveries
+		state.mainWindow.SetContent(createMainUI(state))
+		dialog.ShowInformation(state.tr("update_success_title"), catalogRefreshText(state, refresh), state.mainWindow)
+	}()
 }
 
-// 创建线程安全的下载函数
-func downloadFileWithProgressSafe(url, destPath string, state *AppState, statusUpdates chan<- string, progressUpdates chan<- float64) error {
-	// URL 验证
-	if !strings.HasPrefix(url, "https://files.zohopublic.com.cn") {
-		return fmt.Errorf("无效的下载 URL: %s", url)
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return err
-	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
-	totalBytes := resp.ContentLength
-	file, err := os.Create(destPath)
-	if err != nil {
-		return err
+func handleBatchLiveryInstall(state *AppState) {
+	downloadQueue := state.catalog.selectedLiveries()
+	if len(downloadQueue) == 0 {
+		dialog.ShowInformation(state.tr("no_livery_selected_title"), state.tr("no_livery_selected_message"), state.mainWindow)
+		return
 	}
-	defer file.Close()
-
-	// 使用 bufio.Writer 优化写入性能
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
-
-	if totalBytes <= 0 {
-		statusUpdates <- state.tr("download_no_progress")
-		progressUpdates <- 0.5
-		_, err = io.Copy(writer, resp.Body)
-		return err
+	if !state.isAircraftInstalled || state.ag330Path == "" {
+		dialog.ShowError(fmt.Errorf("%s", state.tr("find_aircraft_dir_error")), state.mainWindow)
+		return
 	}
 
-	// 优化缓冲区大小为 64KB
-	buf := make([]byte, 64*1024)
-	var downloadedBytes int64
-	startTime := time.Now()
-	lastUpdateTime := time.Now()
-
-	for {
-		n, readErr := resp.Body.Read(buf)
-		if n > 0 {
-			if _, writeErr := writer.Write(buf[0:n]); writeErr != nil {
-				return writeErr
-			}
-			downloadedBytes += int64(n)
-
-			// 限制更新频率，每100ms更新一次
-			if time.Since(lastUpdateTime) > 100*time.Millisecond {
-				speed := float64(downloadedBytes) / time.Since(startTime).Seconds() / (1024 * 1024)
-				progress := float64(downloadedBytes) / float64(totalBytes)
-
-				statusUpdates <- state.tr("download_progress_label", float64(downloadedBytes)/(1024*1024), float64(totalBytes)/(1024*1024), speed)
-				progressUpdates <- progress
-				lastUpdateTime = time.Now()
+	// 涂装只下载一次，再解压到每个所选配置档的涂装目录；加入队列后立即开始安装
+	withCompatibleLiveries(state, downloadQueue, func(downloadQueue []Livery) {
+		chooseProfileTargets(state, liveryTargetProfiles(state), func(profiles []string) {
+			liveryDirs := profileLiveryDirs(state, profiles)
+			for _, livery := range downloadQueue {
+				state.liveryQueue.Add(livery.pkg(), liveryDirs)
 			}
-		}
-		if readErr == io.EOF {
-			break
-		}
-		if readErr != nil {
-			return readErr
-		}
-	}
-	return nil
+			state.catalog.clearSelection()
+			refreshCatalogViews(state)
+			state.installLiveryBtn.Disable()
+			runLiveryQueue(state)
+		})
+	})
 }
 
-// 创建线程安全的解压函数
-func extractZipGUISafe(zipFile, destDir string, isAircraft bool, state *AppState, statusUpdates chan<- string, progressUpdates chan<- float64) error {
-	r, err := zip.OpenReader(zipFile)
-	if err != nil {
-		return err
-	}
-	defer r.Close()
-
-	totalFiles := len(r.File)
-	destRoot := destDir
-	if isAircraft {
-		destRoot = filepath.Join(destDir, "AeroGennis Airbus A330-300")
-	}
-
-	lastUpdateTime := time.Now()
-
-	for i, f := range r.File {
-		fpath := filepath.Join(destRoot, f.Name)
-		// 路径安全验证
-		if !strings.HasPrefix(filepath.Clean(fpath), filepath.Clean(destRoot)+string(os.PathSeparator)) {
-			return fmt.Errorf("非法文件路径: %s", fpath)
-		}
-
-		// 限制更新频率
-		if time.Since(lastUpdateTime) > 50*time.Millisecond {
-			progress := float64(i+1) / float64(totalFiles)
-			progressUpdates <- progress
-			statusUpdates <- state.tr("extract_progress_label", f.Name)
-			lastUpdateTime = time.Now()
-		}
-
-		if f.FileInfo().IsDir() {
-			os.MkdirAll(fpath, os.ModePerm)
+// withCompatibleLiveries 去掉要求更新版本飞机的涂装并列出它们，关闭说明后用其余的涂装调用 fn。
+// 以当前配置档中已安装的飞机版本为准。
+func withCompatibleLiveries(state *AppState, liveries []Livery, fn func([]Livery)) {
+	var ok []Livery
+	var blocked []string
+	for _, l := range liveries {
+		if l.requiresNewerAircraft(state.installedAircraftVersion) {
+			blocked = append(blocked, state.tr("livery_min_aircraft_line", l.Name, l.MinAircraftVersion))
 			continue
 		}
-		if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
-			return err
-		}
-		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			return err
-		}
-		rc, err := f.Open()
-		if err != nil {
-			outFile.Close()
-			return err
-		}
-		_, err = io.Copy(outFile, rc)
-		outFile.Close()
-		rc.Close()
-		if err != nil {
-			return err
-		}
+		ok = append(ok, l)
 	}
-	return nil
-}
-
-func checkAircraftInstallation(state *AppState) {
-	state.isAircraftInstalled = false
-	var finalPath string
-	if state.ag330Path != "" {
-		if info, err := os.Stat(state.ag330Path); err == nil && info.IsDir() {
-			finalPath = state.ag330Path
-		}
-	}
-	if finalPath == "" && state.xpPath != "" {
-		foundPath, err := findAerogennisDir(state.xpPath)
-		if err == nil {
-			finalPath = foundPath
-		}
-	}
-	if finalPath != "" {
-		size, err := getDirSize(finalPath)
-		if err == nil {
-			const requiredSize = 1395864371
-			if size > requiredSize {
-				state.isAircraftInstalled = true
-				state.ag330Path = finalPath
-			}
-		}
+	if len(blocked) == 0 {
+		fn(ok)
+		return
 	}
-}
-
-func getDirSize(path string) (int64, error) {
-	var size int64
-	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if !d.IsDir() {
-			info, err := d.Info()
-			if err != nil {
-				return err
-			}
-			size += info.Size()
+	msg := state.tr("livery_requires_newer_aircraft_message", state.installedAircraftVersion, strings.Join(blocked, "\n- "))
+	d := dialog.NewInformation(state.tr("livery_requires_newer_aircraft_title"), msg, state.mainWindow)
+	d.SetOnClosed(func() {
+		if len(ok) > 0 {
+			fn(ok)
 		}
-		return nil
 	})
-	return size, err
+	d.Show()
 }
 
-func validateXPlaneDirectory(path string) (bool, []string) {
-	requiredItems := []string{"Aircraft", "Custom Scenery", "Global Scenery", "Resources", "X-Plane.exe"}
-	var missingItems []string
-	if !filepath.IsAbs(path) {
-		return false, []string{"Path must be an absolute directory path."}
-	}
-	for _, item := range requiredItems {
-		if _, err := os.Stat(filepath.Join(path, item)); os.IsNotExist(err) {
-			missingItems = append(missingItems, item)
-		}
-	}
-	return len(missingItems) == 0, missingItems
-}
-
-func findAerogennisDir(basePath string) (string, error) {
-	aircraftPath := filepath.Join(basePath, "Aircraft")
-	entries, err := os.ReadDir(aircraftPath)
-	if err != nil {
-		return "", fmt.Errorf("could not read Aircraft directory: %w", err)
-	}
-	for _, entry := range entries {
-		if entry.IsDir() && strings.Contains(strings.ToLower(entry.Name()), "aerogennis") {
-			return filepath.Join(aircraftPath, entry.Name()), nil
-		}
-	}
-	return "", fmt.Errorf("no directory containing 'Aerogennis' found in '%s'", aircraftPath)
-}
-
-func readConfig() (xpPath, lang, ag330Path string) {
-	configPath, err := getExecutablePath("Ag330UpdaterConf.txt")
-	if err != nil {
-		return "", "", ""
-	}
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return "", "", ""
-	}
-	lines := strings.Split(string(data), "\n")
-	if len(lines) >= 1 {
-		xpPath = strings.TrimSpace(lines[0])
-	}
-	if len(lines) >= 2 {
-		lang = strings.TrimSpace(lines[1])
-	}
-	if len(lines) >= 3 {
-		ag330Path = strings.TrimSpace(lines[2])
-	}
-	return xpPath, lang, ag330Path
-}
-
-func writeConfig(state *AppState) error {
-	configPath, err := getExecutablePath("Ag330UpdaterConf.txt")
-	if err != nil {
-		return err
+// checkAircraftInstallation 以 .acf 文件判断飞机是否已安装，并读取已安装的版本号。
+func checkAircraftInstallation(state *AppState) {
+	state.isAircraftInstalled = false
+	state.installedAircraftVersion = ""
+	if path, ok := engine.FindInstalledAircraft(state.xpPath, state.ag330Path); ok {
+		state.isAircraftInstalled = true
+		state.ag330Path = path
+		state.installedAircraftVersion = engine.DetectAircraftVersion(path)
 	}
-	content := fmt.Sprintf("%s\n%s\n%s", state.xpPath, state.language, state.ag330Path)
-	return os.WriteFile(configPath, []byte(content), 0644)
 }
 
 // 翻译部分保持不变
 var translations = map[string]map[string]string{
 	"en-US": {
-		"window_title":                             "AeroGennis A330-300 Installer - v2025.8.3.20-Preview",
+		"window_title":                             "AeroGennis A330-300 Installer - v%s",
 		"reinstall_button":                         "Check for Updates / Reinstall",
 		"aircraft_installed_title":                 "Installation Detected",
 		"aircraft_installed_desc":                  "The application has detected that AeroGennis A330-300 is already installed. You can check for updates or reinstall if needed.",
@@ -1063,12 +806,48 @@ var translations = map[string]map[string]string{
 		"save_success_title":                       "Saved",
 		"save_manual_path_success":                 "Manual path has been saved successfully. The application has been refreshed.",
 		"setup_welcome":                            "Welcome! Please select your X-Plane 12 root directory to begin.",
+		"discovered_installs_label":                "Detected X-Plane 12 installations:",
+		"discovered_none_label":                    "No X-Plane 12 installation was detected automatically. Please enter or browse to the directory.",
+		"profiles_label":                           "X-Plane Profiles",
+		"download_settings_label":                  "Downloads",
+		"concurrency_label":                        "Parallel downloads",
+		"adaptive_concurrency_check":               "Adjust automatically based on speed and errors",
+		"bandwidth_limit_label":                    "Speed limit (KB/s, 0 = unlimited)",
+		"bandwidth_limit_error":                    "The speed limit must be a whole number of KB/s, 0 or greater.",
+		"save_download_settings_button":            "Save Download Settings",
+		"download_settings_saved":                  "Download settings saved. They apply to downloads immediately and to parallel downloads from the next queue run.",
+		"bandwidth_schedule_label":                 "Time-of-day speed limits (override the limit above; the first matching rule applies):",
+		"bandwidth_no_rules_label":                 "No time-of-day rules.",
+		"bandwidth_add_rule_button":                "Add Time Rule",
+		"bandwidth_rule_unlimited":                 "%s – %s: unlimited",
+		"bandwidth_rule_limited":                   "%s – %s: %d KB/s",
+		"bandwidth_rule_start_label":               "From (HH:MM)",
+		"bandwidth_rule_end_label":                 "Until (HH:MM)",
+		"bandwidth_rule_time_error":                "Enter the times as HH:MM in 24-hour format, for example 22:00.",
+		"new_profile_button":                       "New Profile",
+		"delete_profile_button":                    "Delete Current Profile",
+		"new_profile_title":                        "New Profile",
+		"profile_name_label":                       "Profile name",
+		"profile_name_error":                       "Cannot create profile",
+		"delete_profile_confirm_title":             "Delete Profile",
+		"delete_profile_confirm_message":           "Delete the profile '%s'? Installed files are not removed.",
+		"install_targets_title":                    "Choose Profiles",
+		"install_targets_message":                  "Install to the following profiles:",
+		"status_installing_for_profile":            "Installing for profile '%s'...",
 		"path_placeholder":                         "Enter or browse to your X-Plane 12 root directory...",
 		"browse_button":                            "Browse...",
 		"save_continue_button":                     "Save and Continue",
 		"invalid_xp_path_error":                    "The selected directory is not a valid X-Plane 12 installation.",
 		"missing_items_label":                      "Missing items:",
 		"save_config_error":                        "Failed to save configuration",
+		"config_load_error":                        "Failed to read configuration",
+		"queue_load_error":                         "Could not read the saved install queue",
+		"preview_cache_error":                      "Could not open the livery preview cache, only the previews of installed liveries are shown",
+		"config_warnings_title":                    "Some settings were ignored",
+		"config_rule_ignored_warning":              "The download speed rule %s-%s was ignored: %v",
+		"config_insecure_source_warning":           "The livery list source %s was ignored because %s does not use https",
+		"config_newer_schema_warning":              "%s was saved by a newer version of the installer (schema_version %d, this version supports %d). Settings this version does not know are kept but not used.",
+		"config_corrupt_message":                   "The configuration file could not be read and default settings are being used: %v\n\nThe original file was kept as:\n%s",
 		"status_ready":                             "Ready. Select an option.",
 		"tab_aircraft":                             "Aircraft",
 		"tab_liveries":                             "Liveries",
@@ -1082,7 +861,7 @@ var translations = map[string]map[string]string{
 		"liveries_selected_status":                 "%d liveries selected.",
 		"no_livery_selected_status":                "No livery selected. Check a box to begin.",
 		"update_tab_title":                         "Update This Application",
-		"update_tab_desc":                          "This will download the latest version of this installer (as a .exe file). You will need to close this application and run the new one manually.",
+		"update_tab_desc":                          "This will download the latest version of this installer next to the current program, replace it and restart automatically. If the new version fails to start, the current version is restored.",
 		"update_tab_warning":                       "Note: This file is not digitally signed and may be flagged by antivirus software. This is a false positive.",
 		"download_latest_button":                   "Download Latest Application Version",
 		"settings_tab_title":                       "Application Settings",
@@ -1104,24 +883,121 @@ var translations = map[string]map[string]string{
 		"find_aircraft_dir_error":                  "Could not find a valid AeroGennis A330 directory. Please install the aircraft first or set the path manually in Settings.",
 		"status_downloading_update":                "Downloading new application version...",
 		"download_update_error":                    "Failed to download update",
-		"update_download_complete_status":          "Update downloaded successfully!",
-		"update_download_complete_title":           "Download Complete",
-		"update_download_complete_message":         "New version saved to:\n%s\nPlease close this application and run the new one.",
+		"update_confirm_title":                     "Update Installer",
+		"update_confirm_message":                   "The installer will download the new version, replace itself and restart. Continue?",
+		"status_installing_update":                 "Replacing the current program...",
+		"status_restarting":                        "Starting the new version...",
+		"operation_warning_status":                 "Warning: %v",
+		"livery_requires_newer_aircraft_title":     "Aircraft update required",
+		"livery_requires_newer_aircraft_message":   "These liveries require a newer aircraft than the installed version %s and will be skipped:\n- %s",
+		"livery_min_aircraft_line":                 "%s (requires %s)",
+		"livery_requires_newer_aircraft_error":     "requires aircraft version %s or later (installed: %s)",
+		"update_unverified_error":                  "the version manifest does not publish a checksum for this version, so it cannot be installed safely",
+		"update_unverified_confirm_message":        "The version manifest does not publish a checksum for this version, so the download cannot be verified. Only install it if you trust the download source. Update anyway?",
+		"download_unverified_status":               "Download finished, but no checksum was published, so the file could not be verified",
+		"aircraft_unverified_warning":              "Warning: the version manifest does not publish a checksum for the aircraft package, so the downloaded package could not be verified.",
+		"aircraft_manifest_unavailable_warning":    "Warning: the version manifest could not be downloaded (%s), so the aircraft package could not be verified.",
+		"concurrency_adjusted_status":              "Simultaneous downloads adjusted to %d",
+		"update_install_error":                     "Failed to install update",
+		"update_rollback_error":                    "The new version failed to start and the previous version has been restored",
 		"download_progress_label":                  "Downloading... %.2f / %.2f MB (%.2f MB/s)",
 		"download_no_progress":                     "Warning: Content length unknown. Progress will not be shown.",
 		"extract_progress_label":                   "Extracting: %s",
 		"install_selected_liveries_button":         "Install Selected Liveries",
+		"livery_search_placeholder":                "Search name, airline, registration or ICAO code",
+		"filter_all_types":                         "Real and fictional",
+		"filter_real":                              "Real only",
+		"filter_fictional":                         "Fictional only",
+		"filter_all_install_states":                "Installed or not",
+		"filter_installed":                         "Installed",
+		"filter_not_installed":                     "Not installed",
+		"filter_updates_only":                      "Updates only",
+		"group_by_airline":                         "Group by airline",
+		"sort_by_name":                             "Sort by name",
+		"sort_by_airline":                          "Sort by airline",
+		"sort_by_registration":                     "Sort by registration",
+		"select_shown_button":                      "Select Shown",
+		"clear_selection_button":                   "Clear Selection",
+		"catalog_count_label":                      "%d of %d shown",
+		"livery_reconcile_error":                   "Could not check the livery folders: %v",
+		"airline_group_label":                      "%s (%d)",
+		"no_airline_group":                         "No airline",
+		"gallery_view_check":                       "Gallery",
+		"preview_none_label":                       "Click a livery to preview it",
+		"preview_airline_label":                    "Airline: %s",
+		"preview_registration_label":               "Registration: %s",
+		"preview_author_label":                     "Author: %s",
+		"preview_real_label":                       "Real livery",
+		"preview_fictional_label":                  "Fictional livery",
+		"badge_installed":                          "Installed",
+		"badge_outdated":                           "Update available",
+		"badge_not_installed":                      "Not installed",
+		"preview_version_label":                    "Version: %s",
+		"preview_installed_label":                  "Installed in: %s",
+		"preview_outdated_label":                   "Installed, but the catalog has a newer package",
+		"preview_outdated_version_label":           "Installed version %s is outdated",
+		"update_outdated_button":                   "Update Outdated (%d)",
+		"update_outdated_title":                    "Update Outdated Liveries",
+		"update_outdated_confirm_message":          "Reinstall these %d outdated liveries?\n\n- %s",
+		"no_outdated_liveries_message":             "All installed liveries are up to date.",
+		"uninstall_livery_entry":                   "%s (%s)",
 		"update_livery_list_button":                "Update Livery List",
 		"uninstall_liveries_button":                "Uninstall Liveries...",
-		"status_updating_livery_list":              "Downloading latest livery list...",
 		"livery_list_download_error":               "Failed to download livery list",
 		"livery_list_read_error":                   "Failed to read downloaded livery list data",
 		"livery_list_save_error":                   "Failed to save livery list file",
+		"catalog_state_save_error":                 "The list was refreshed but the refresh record could not be saved",
 		"update_success_title":                     "Updated",
 		"livery_list_update_success":               "Livery list has been successfully updated and reloaded!",
+		"livery_list_not_modified":                 "The livery list is already up to date.",
+		"livery_list_invalid_error":                "The downloaded livery list is invalid, the current list was kept",
+		"livery_list_empty_error":                  "The downloaded livery list contains no liveries, the current list was kept",
+		"catalog_diff_none":                        "No liveries were added, removed or changed.",
+		"catalog_diff_added":                       "Added (%d):",
+		"catalog_diff_removed":                     "Removed (%d):",
+		"catalog_diff_changed":                     "Changed (%d):",
+		"catalog_diff_more":                        "… and %d more",
+		"catalog_last_refreshed_label":             "Livery list last refreshed: %s",
+		"catalog_never_refreshed_label":            "Livery list has not been refreshed yet",
+		"status_updating_catalog_source":           "Updating livery list from %s...",
+		"catalog_source_failures":                  "These sources could not be refreshed and keep their previous list (%d):",
+		"preview_source_label":                     "Source: %s",
+		"catalog_sources_label":                    "Livery list sources",
+		"catalog_sources_hint":                     "Liveries from all enabled sources are merged. When several sources list the same livery, the one with the highest priority is used. New remote sources are downloaded on the next livery list update.",
+		"catalog_source_add_button":                "Add source",
+		"catalog_source_edit_title":                "Edit source",
+		"catalog_source_name_label":                "Name",
+		"catalog_source_location_label":            "Location",
+		"catalog_source_location_placeholder":      "https://example.com/liveries.json or a local file/folder",
+		"catalog_source_priority_label":            "Priority",
+		"catalog_source_enabled_check":             "Enabled",
+		"catalog_source_info":                      "Priority %d · %s",
+		"catalog_source_delete_title":              "Remove source",
+		"catalog_source_delete_message":            "Remove the livery list source \"%s\"?",
+		"catalog_source_name_error":                "Please enter a name for the source",
+		"catalog_source_duplicate_error":           "A source named \"%s\" already exists",
+		"catalog_source_https_error":               "Remote sources must use an https:// link",
+		"catalog_source_location_error":            "Cannot find the file or folder: %s",
+		"catalog_source_priority_error":            "Priority must be a whole number",
+		"catalog_source_last_error":                "At least one livery list source is required",
 		"livery_list_load_fail":                    "Could not load livery list. Please try updating it.",
 		"batch_download_progress_label":            "Downloading (%d/%d): %s",
-		"batch_install_complete_status":            "%d liveries processed.",
+		"queue_title":                              "Install Queue",
+		"queue_start_button":                       "Start Queue",
+		"queue_clear_finished_button":              "Clear Finished",
+		"queue_row_label":                          "%s (%s)",
+		"queue_pending_status":                     "%d livery installs are waiting in the queue. Press Start Queue on the Liveries tab to continue.",
+		"job_state_queued":                         "waiting",
+		"job_state_running":                        "installing",
+		"job_state_paused":                         "paused",
+		"job_state_done":                           "installed",
+		"job_state_failed":                         "failed",
+		"batch_install_report_status":              "Liveries installed: %d, failed: %d.",
+		"report_title":                             "Install Report",
+		"report_succeeded_label":                   "Installed (%d):",
+		"report_failed_label":                      "Failed (%d):",
+		"queue_retry_failed_button":                "Retry Failed",
+		"close_button":                             "Close",
 		"batch_install_complete_message":           "%d selected liveries have been processed and installed.",
 		"scan_liveries_dir_error":                  "Failed to scan the liveries directory",
 		"no_installed_liveries_title":              "No Liveries Found",
@@ -1129,6 +1005,8 @@ var translations = map[string]map[string]string{
 		"uninstall_dialog_title":                   "Select Liveries to Uninstall",
 		"confirm_button":                           "Confirm",
 		"cancel_button":                            "Cancel",
+		"status_cancelling":                        "Cancelling...",
+		"operation_cancelled_status":               "Cancelled. Partial downloads and extracted files have been removed.",
 		"uninstall_final_confirm_title":            "Permanent Deletion",
 		"uninstall_final_confirm_message":          "Are you sure you want to permanently delete these %d selected folders?\n\n- %s",
 		"uninstall_complete_title":                 "Uninstallation Report",
@@ -1145,9 +1023,38 @@ var translations = map[string]map[string]string{
 		"self_uninstall_warning":                   "WARNING: This will remove the installer executable itself, along with its configuration and livery list files. You will need to re-download the application to use it again.",
 		"self_uninstall_confirm_title":             "Confirm Application Uninstallation",
 		"self_uninstall_confirm_message":           "Are you sure you want to completely remove this application and its related files from your computer?",
+		"download_retry_status":                    "Attempt %d/%d failed: %v. Retrying in %.0f s...",
+		"attempt_history_label":                    "Attempt history:",
+		"attempt_history_line":                     "Attempt %d: %v",
+		"livery_download_failed_status":            "Failed to download %s: %v",
+		"integrity_sha256_mismatch_error":          "Integrity check failed: the downloaded file's SHA-256 does not match the published value.\nExpected: %s\nActual: %s\nThe file was discarded and nothing was installed.",
+		"integrity_size_mismatch_error":            "Integrity check failed: expected %d bytes but the download has %d bytes.\nThe file was discarded and nothing was installed.",
+		"status_swapping_install":                  "Verifying and replacing the previous installation...",
+		"install_failed_status":                    "Installation failed.",
+		"install_verify_error":                     "The new aircraft files could not be installed",
+		"install_rolled_back_message":              "Your previous installation has been restored and is unchanged.",
+		"verify_aircraft_button":                   "Verify Installed Files",
+		"verify_title":                             "File Verification",
+		"verify_no_receipt_message":                "No install record was found for this aircraft. Reinstall it once with this version of the installer to enable verification.",
+		"verify_progress_label":                    "Verifying files... %d / %d",
+		"verify_ok_message":                        "All %d installed files are present and unchanged.",
+		"verify_problems_message":                  "%d files are missing or modified:\n%s\n\nReinstall the aircraft to repair them.",
+		"verify_missing_file":                      "Missing: %s",
+		"verify_changed_file":                      "Modified: %s",
+		"installed_version_label":                  "Installed version: %s",
+		"latest_version_label":                     "Latest available: %s",
+		"published_on_label":                       "published %s",
+		"version_unknown":                          "unknown",
+		"version_checking":                         "checking...",
+		"version_unavailable":                      "could not be checked",
+		"update_available_label":                   "An update is available for the aircraft.",
+		"up_to_date_label":                         "The installed aircraft is up to date.",
+		"update_aircraft_button":                   "Update AeroGennis A330-300",
+		"current_app_version_label":                "Current version: %s",
+		"app_update_available_banner":              "A newer version of this installer is available: %s (you have %s).",
 	},
 	"zh-CN": {
-		"window_title":                             "AeroGennis A330-300 安装程序 - v2025.8.3.20-Preview",
+		"window_title":                             "AeroGennis A330-300 安装程序 - v%s",
 		"reinstall_button":                         "检查更新/重新安装",
 		"aircraft_installed_title":                 "检测到已安装",
 		"aircraft_installed_desc":                  "程序检测到 AeroGennis A330-300 已经安装。如果需要，您可以检查更新或重新安装。",
@@ -1158,12 +1065,48 @@ var translations = map[string]map[string]string{
 		"save_success_title":                       "已保存",
 		"save_manual_path_success":                 "手动路径已成功保存。应用程序已刷新。",
 		"setup_welcome":                            "欢迎！请选择您的 X-Plane 12 根目录以开始。",
+		"discovered_installs_label":                "检测到的 X-Plane 12 安装：",
+		"discovered_none_label":                    "未能自动检测到 X-Plane 12 安装，请手动输入或浏览选择目录。",
+		"profiles_label":                           "X-Plane 配置档",
+		"download_settings_label":                  "下载",
+		"concurrency_label":                        "同时下载数",
+		"adaptive_concurrency_check":               "根据速度和错误自动调整",
+		"bandwidth_limit_label":                    "速度上限（KB/s，0 为不限）",
+		"bandwidth_limit_error":                    "速度上限必须是不小于 0 的整数（KB/s）。",
+		"save_download_settings_button":            "保存下载设置",
+		"download_settings_saved":                  "下载设置已保存。速度上限立即生效，同时下载数从下次运行队列开始生效。",
+		"bandwidth_schedule_label":                 "按时段的速度上限（优先于上面的上限，使用第一条匹配的规则）：",
+		"bandwidth_no_rules_label":                 "没有时段规则。",
+		"bandwidth_add_rule_button":                "添加时段规则",
+		"bandwidth_rule_unlimited":                 "%s – %s：不限速",
+		"bandwidth_rule_limited":                   "%s – %s：%d KB/s",
+		"bandwidth_rule_start_label":               "开始（HH:MM）",
+		"bandwidth_rule_end_label":                 "结束（HH:MM）",
+		"bandwidth_rule_time_error":                "请按 24 小时制输入 HH:MM 格式的时间，例如 22:00。",
+		"new_profile_button":                       "新建配置档",
+		"delete_profile_button":                    "删除当前配置档",
+		"new_profile_title":                        "新建配置档",
+		"profile_name_label":                       "配置档名称",
+		"profile_name_error":                       "无法创建配置档",
+		"delete_profile_confirm_title":             "删除配置档",
+		"delete_profile_confirm_message":           "删除配置档 '%s'？已安装的文件不会被删除。",
+		"install_targets_title":                    "选择配置档",
+		"install_targets_message":                  "安装到以下配置档：",
+		"status_installing_for_profile":            "正在为配置档 '%s' 安装...",
 		"path_placeholder":                         "输入或浏览您的 X-Plane 12 根目录...",
 		"browse_button":                            "浏览...",
 		"save_continue_button":                     "保存并继续",
 		"invalid_xp_path_error":                    "所选目录不是有效的 X-Plane 12 安装目录。",
 		"missing_items_label":                      "缺少项目:",
 		"save_config_error":                        "无法保存配置",
+		"config_load_error":                        "无法读取配置",
+		"queue_load_error":                         "无法读取保存的安装队列",
+		"preview_cache_error":                      "无法打开涂装预览图缓存，只显示已安装涂装自带的预览图",
+		"config_warnings_title":                    "部分设置被忽略",
+		"config_rule_ignored_warning":              "已忽略下载速度规则 %s-%s：%v",
+		"config_insecure_source_warning":           "已忽略涂装列表来源 %s：%s 没有使用 https",
+		"config_newer_schema_warning":              "%s 由较新版本的安装器保存（schema_version %d，本程序支持 %d）。本程序不认识的设置会被保留，但不会生效。",
+		"config_corrupt_message":                   "配置文件无法读取，现在使用默认设置：%v\n\n原文件已备份为：\n%s",
 		"status_ready":                             "准备就绪。请选择一个选项。",
 		"tab_aircraft":                             "飞机",
 		"tab_liveries":                             "涂装",
@@ -1177,7 +1120,7 @@ var translations = map[string]map[string]string{
 		"liveries_selected_status":                 "已选择 %d 个涂装。",
 		"no_livery_selected_status":                "未选择涂装。请勾选以开始。",
 		"update_tab_title":                         "更新此应用程序",
-		"update_tab_desc":                          "这将下载此安装程序的最新版本（.exe 文件）。您需要关闭此应用程序并手动运行新程序。",
+		"update_tab_desc":                          "这将在当前程序旁下载此安装程序的最新版本，替换当前程序并自动重启。如果新版本无法启动，将恢复当前版本。",
 		"update_tab_warning":                       "注意：此文件未经数字签名，可能会被杀毒软件标记。这是一个误报。",
 		"download_latest_button":                   "下载最新应用程序版本",
 		"settings_tab_title":                       "应用程序设置",
@@ -1199,24 +1142,121 @@ var translations = map[string]map[string]string{
 		"find_aircraft_dir_error":                  "未能找到有效的 AeroGennis A330 目录。请先安装飞机，或在设置中手动指定路径。",
 		"status_downloading_update":                "正在下载新版应用程序...",
 		"download_update_error":                    "下载更新失败",
-		"update_download_complete_status":          "更新下载成功！",
-		"update_download_complete_title":           "下载完成",
-		"update_download_complete_message":         "新版本已保存至：\n%s\n请关闭此应用程序并运行新程序。",
+		"update_confirm_title":                     "更新安装程序",
+		"update_confirm_message":                   "安装程序将下载新版本、替换自身并重新启动。是否继续？",
+		"status_installing_update":                 "正在替换当前程序...",
+		"status_restarting":                        "正在启动新版本...",
+		"operation_warning_status":                 "警告：%v",
+		"livery_requires_newer_aircraft_title":     "需要更新飞机",
+		"livery_requires_newer_aircraft_message":   "以下涂装需要比已安装的 %s 更新的飞机版本，将被跳过：\n- %s",
+		"livery_min_aircraft_line":                 "%s（需要 %s）",
+		"livery_requires_newer_aircraft_error":     "需要飞机版本 %s 或更高（已安装：%s）",
+		"update_unverified_error":                  "版本清单没有公布该版本的校验值，无法安全地安装",
+		"update_unverified_confirm_message":        "版本清单没有公布该版本的校验值，下载的程序无法校验。只有在信任下载来源时才应安装。仍要更新吗？",
+		"download_unverified_status":               "下载完成，但发布者没有公布校验值，文件未经校验",
+		"aircraft_unverified_warning":              "警告：版本清单没有公布飞机包的校验值，下载的飞机包未经校验。",
+		"aircraft_manifest_unavailable_warning":    "警告：无法下载版本清单（%s），飞机包未经校验。",
+		"concurrency_adjusted_status":              "同时下载数调整为 %d",
+		"update_install_error":                     "安装更新失败",
+		"update_rollback_error":                    "新版本无法启动，已恢复旧版本",
 		"download_progress_label":                  "下载中... %.2f / %.2f MB (%.2f MB/s)",
 		"download_no_progress":                     "警告：内容长度未知。将不显示进度。",
 		"extract_progress_label":                   "正在解压: %s",
 		"install_selected_liveries_button":         "安装所选涂装",
+		"livery_search_placeholder":                "搜索名称、航空公司、注册号或 ICAO 代码",
+		"filter_all_types":                         "真实和虚构",
+		"filter_real":                              "仅真实",
+		"filter_fictional":                         "仅虚构",
+		"filter_all_install_states":                "全部安装状态",
+		"filter_installed":                         "已安装",
+		"filter_not_installed":                     "未安装",
+		"filter_updates_only":                      "仅有更新",
+		"group_by_airline":                         "按航空公司分组",
+		"sort_by_name":                             "按名称排序",
+		"sort_by_airline":                          "按航空公司排序",
+		"sort_by_registration":                     "按注册号排序",
+		"select_shown_button":                      "选择显示的涂装",
+		"clear_selection_button":                   "清除选择",
+		"catalog_count_label":                      "显示 %d / %d",
+		"livery_reconcile_error":                   "无法对照涂装文件夹：%v",
+		"airline_group_label":                      "%s（%d）",
+		"no_airline_group":                         "无航空公司",
+		"gallery_view_check":                       "画廊",
+		"preview_none_label":                       "点击涂装查看预览",
+		"preview_airline_label":                    "航空公司：%s",
+		"preview_registration_label":               "注册号：%s",
+		"preview_author_label":                     "作者：%s",
+		"preview_real_label":                       "真实涂装",
+		"preview_fictional_label":                  "虚构涂装",
+		"badge_installed":                          "已安装",
+		"badge_outdated":                           "有更新",
+		"badge_not_installed":                      "未安装",
+		"preview_version_label":                    "版本：%s",
+		"preview_installed_label":                  "安装在：%s",
+		"preview_outdated_label":                   "已安装，目录中有更新的包",
+		"preview_outdated_version_label":           "已安装的版本 %s 已过期",
+		"update_outdated_button":                   "更新过期涂装（%d）",
+		"update_outdated_title":                    "更新过期涂装",
+		"update_outdated_confirm_message":          "要重新安装这 %d 个过期的涂装吗？\n\n- %s",
+		"no_outdated_liveries_message":             "已安装的涂装都是最新的。",
+		"uninstall_livery_entry":                   "%s（%s）",
 		"update_livery_list_button":                "更新涂装列表",
 		"uninstall_liveries_button":                "卸载涂装...",
-		"status_updating_livery_list":              "正在下载最新的涂装列表...",
 		"livery_list_download_error":               "下载涂装列表失败",
 		"livery_list_read_error":                   "读取下载的涂装列表数据失败",
 		"livery_list_save_error":                   "保存涂装列表文件失败",
+		"catalog_state_save_error":                 "涂装列表已刷新，但无法保存刷新记录",
 		"update_success_title":                     "已更新",
 		"livery_list_update_success":               "涂装列表已成功更新并重新加载！",
+		"livery_list_not_modified":                 "涂装列表已是最新。",
+		"livery_list_invalid_error":                "下载的涂装列表无效，已保留当前列表",
+		"livery_list_empty_error":                  "下载的涂装列表中没有涂装，已保留当前列表",
+		"catalog_diff_none":                        "没有新增、删除或变化的涂装。",
+		"catalog_diff_added":                       "新增（%d）：",
+		"catalog_diff_removed":                     "删除（%d）：",
+		"catalog_diff_changed":                     "变化（%d）：",
+		"catalog_diff_more":                        "……以及另外 %d 个",
+		"catalog_last_refreshed_label":             "涂装列表上次刷新：%s",
+		"catalog_never_refreshed_label":            "涂装列表尚未刷新",
+		"status_updating_catalog_source":           "正在从 %s 更新涂装列表...",
+		"catalog_source_failures":                  "以下来源刷新失败，继续使用上次的列表 (%d)：",
+		"preview_source_label":                     "来源：%s",
+		"catalog_sources_label":                    "涂装列表来源",
+		"catalog_sources_hint":                     "所有启用的来源中的涂装会合并显示。多个来源包含同一涂装时，使用优先级最高的来源。新添加的远程来源会在下次更新涂装列表时下载。",
+		"catalog_source_add_button":                "添加来源",
+		"catalog_source_edit_title":                "编辑来源",
+		"catalog_source_name_label":                "名称",
+		"catalog_source_location_label":            "位置",
+		"catalog_source_location_placeholder":      "https://example.com/liveries.json 或本地文件/文件夹",
+		"catalog_source_priority_label":            "优先级",
+		"catalog_source_enabled_check":             "启用",
+		"catalog_source_info":                      "优先级 %d · %s",
+		"catalog_source_delete_title":              "删除来源",
+		"catalog_source_delete_message":            "确定删除涂装列表来源“%s”吗？",
+		"catalog_source_name_error":                "请输入来源名称",
+		"catalog_source_duplicate_error":           "已存在名为“%s”的来源",
+		"catalog_source_https_error":               "远程来源必须使用 https:// 链接",
+		"catalog_source_location_error":            "找不到文件或文件夹：%s",
+		"catalog_source_priority_error":            "优先级必须是整数",
+		"catalog_source_last_error":                "至少需要保留一个涂装列表来源",
 		"livery_list_load_fail":                    "无法加载涂装列表。请尝试更新它。",
 		"batch_download_progress_label":            "下载中 (%d/%d): %s",
-		"batch_install_complete_status":            "%d 个涂装处理完毕。",
+		"queue_title":                              "安装队列",
+		"queue_start_button":                       "开始队列",
+		"queue_clear_finished_button":              "清除已结束",
+		"queue_row_label":                          "%s（%s）",
+		"queue_pending_status":                     "队列中有 %d 个涂装等待安装。在涂装页点击“开始队列”继续。",
+		"job_state_queued":                         "等待中",
+		"job_state_running":                        "安装中",
+		"job_state_paused":                         "已暂停",
+		"job_state_done":                           "已安装",
+		"job_state_failed":                         "失败",
+		"batch_install_report_status":              "涂装安装成功 %d 个，失败 %d 个。",
+		"report_title":                             "安装报告",
+		"report_succeeded_label":                   "安装成功（%d）：",
+		"report_failed_label":                      "安装失败（%d）：",
+		"queue_retry_failed_button":                "重试失败项",
+		"close_button":                             "关闭",
 		"batch_install_complete_message":           "%d 个选中的涂装已处理并安装。",
 		"scan_liveries_dir_error":                  "扫描涂装目录失败",
 		"no_installed_liveries_title":              "未找到涂装",
@@ -1224,6 +1264,8 @@ var translations = map[string]map[string]string{
 		"uninstall_dialog_title":                   "选择要卸载的涂装",
 		"confirm_button":                           "确认",
 		"cancel_button":                            "取消",
+		"status_cancelling":                        "正在取消...",
+		"operation_cancelled_status":               "已取消。未完成的下载和解压文件已删除。",
 		"uninstall_final_confirm_title":            "永久删除",
 		"uninstall_final_confirm_message":          "您确定要永久删除这 %d 个选中的文件夹吗？\n\n- %s",
 		"uninstall_complete_title":                 "卸载报告",
@@ -1237,12 +1279,41 @@ var translations = map[string]map[string]string{
 		"uninstall_aircraft_error":                 "卸载机模失败",
 		"uninstall_aircraft_success_message":       "AeroGennis A330-300 已成功卸载。",
 		"self_uninstall_button":                    "卸载此应用程序",
-		"self_uninstall_warning":                   "警告：这将移除安装程序本身（.exe）、其配置文件和涂装列表文件。您需要重新下载才能再次使用本程序。",
+		"self_uninstall_warning":                   "警告：这将移除安装程序本身、其配置文件和涂装列表文件。您需要重新下载才能再次使用本程序。",
 		"self_uninstall_confirm_title":             "确认卸载应用程序",
 		"self_uninstall_confirm_message":           "您确定要从您的电脑上完全移除此应用程序及其相关文件吗？",
+		"download_retry_status":                    "第 %d/%d 次尝试失败：%v。%.0f 秒后重试...",
+		"attempt_history_label":                    "尝试记录：",
+		"attempt_history_line":                     "第 %d 次：%v",
+		"livery_download_failed_status":            "下载 %s 失败：%v",
+		"integrity_sha256_mismatch_error":          "完整性校验失败：下载文件的 SHA-256 与发布值不一致。\n期望：%s\n实际：%s\n文件已丢弃，未安装任何内容。",
+		"integrity_size_mismatch_error":            "完整性校验失败：期望 %d 字节，实际下载 %d 字节。\n文件已丢弃，未安装任何内容。",
+		"status_swapping_install":                  "正在校验并替换旧版本...",
+		"install_failed_status":                    "安装失败。",
+		"install_verify_error":                     "无法安装新的飞机文件",
+		"install_rolled_back_message":              "已恢复您之前的安装，未做任何改动。",
+		"verify_aircraft_button":                   "校验已安装文件",
+		"verify_title":                             "文件校验",
+		"verify_no_receipt_message":                "没有找到此飞机的安装记录。请使用此版本的安装程序重新安装一次以启用校验。",
+		"verify_progress_label":                    "正在校验文件... %d / %d",
+		"verify_ok_message":                        "全部 %d 个已安装文件均存在且未被修改。",
+		"verify_problems_message":                  "%d 个文件缺失或已被修改：\n%s\n\n请重新安装飞机以修复。",
+		"verify_missing_file":                      "缺失：%s",
+		"verify_changed_file":                      "已修改：%s",
+		"installed_version_label":                  "已安装版本：%s",
+		"latest_version_label":                     "最新可用版本：%s",
+		"published_on_label":                       "发布于 %s",
+		"version_unknown":                          "未知",
+		"version_checking":                         "正在检查...",
+		"version_unavailable":                      "无法检查",
+		"update_available_label":                   "飞机有可用更新。",
+		"up_to_date_label":                         "已安装的飞机是最新版本。",
+		"update_aircraft_button":                   "更新 AeroGennis A330-300",
+		"current_app_version_label":                "当前版本：%s",
+		"app_update_available_banner":              "安装程序有新版本可用：%s（当前为 %s）。",
 	},
 	"zh-TW": {
-		"window_title":                             "AeroGennis A330-300 安裝程式 - v2025.8.3.20-Preview",
+		"window_title":                             "AeroGennis A330-300 安裝程式 - v%s",
 		"reinstall_button":                         "檢查更新/重新安裝",
 		"aircraft_installed_title":                 "偵測到已安裝",
 		"aircraft_installed_desc":                  "應用程式偵測到 AeroGennis A330-300 已經安裝。如果需要，您可以檢查更新或重新安裝。",
@@ -1253,12 +1324,48 @@ var translations = map[string]map[string]string{
 		"save_success_title":                       "已儲存",
 		"save_manual_path_success":                 "手動路徑已成功儲存。應用程式已重新整理。",
 		"setup_welcome":                            "歡迎！請選擇您的 X-Plane 12 根目錄以開始。",
+		"discovered_installs_label":                "偵測到的 X-Plane 12 安裝：",
+		"discovered_none_label":                    "未能自動偵測到 X-Plane 12 安裝，請手動輸入或瀏覽選擇目錄。",
+		"profiles_label":                           "X-Plane 設定檔",
+		"download_settings_label":                  "下載",
+		"concurrency_label":                        "同時下載數",
+		"adaptive_concurrency_check":               "根據速度和錯誤自動調整",
+		"bandwidth_limit_label":                    "速度上限（KB/s，0 為不限）",
+		"bandwidth_limit_error":                    "速度上限必須是不小於 0 的整數（KB/s）。",
+		"save_download_settings_button":            "儲存下載設定",
+		"download_settings_saved":                  "下載設定已儲存。速度上限立即生效，同時下載數從下次執行佇列開始生效。",
+		"bandwidth_schedule_label":                 "按時段的速度上限（優先於上面的上限，使用第一條符合的規則）：",
+		"bandwidth_no_rules_label":                 "沒有時段規則。",
+		"bandwidth_add_rule_button":                "新增時段規則",
+		"bandwidth_rule_unlimited":                 "%s – %s：不限速",
+		"bandwidth_rule_limited":                   "%s – %s：%d KB/s",
+		"bandwidth_rule_start_label":               "開始（HH:MM）",
+		"bandwidth_rule_end_label":                 "結束（HH:MM）",
+		"bandwidth_rule_time_error":                "請按 24 小時制輸入 HH:MM 格式的時間，例如 22:00。",
+		"new_profile_button":                       "新增設定檔",
+		"delete_profile_button":                    "刪除目前設定檔",
+		"new_profile_title":                        "新增設定檔",
+		"profile_name_label":                       "設定檔名稱",
+		"profile_name_error":                       "無法建立設定檔",
+		"delete_profile_confirm_title":             "刪除設定檔",
+		"delete_profile_confirm_message":           "刪除設定檔 '%s'？已安裝的檔案不會被刪除。",
+		"install_targets_title":                    "選擇設定檔",
+		"install_targets_message":                  "安裝到以下設定檔：",
+		"status_installing_for_profile":            "正在為設定檔 '%s' 安裝...",
 		"path_placeholder":                         "輸入或瀏覽您的 X-Plane 12 根目錄...",
 		"browse_button":                            "瀏覽...",
 		"save_continue_button":                     "儲存並繼續",
 		"invalid_xp_path_error":                    "所選目錄不是有效的 X-Plane 12 安裝目錄。",
 		"missing_items_label":                      "缺少項目:",
 		"save_config_error":                        "無法儲存設定",
+		"config_load_error":                        "無法讀取設定",
+		"queue_load_error":                         "無法讀取儲存的安裝佇列",
+		"preview_cache_error":                      "無法開啟塗裝預覽圖快取，只顯示已安裝塗裝自帶的預覽圖",
+		"config_warnings_title":                    "部分設定被忽略",
+		"config_rule_ignored_warning":              "已忽略下載速度規則 %s-%s：%v",
+		"config_insecure_source_warning":           "已忽略塗裝列表來源 %s：%s 沒有使用 https",
+		"config_newer_schema_warning":              "%s 由較新版本的安裝器儲存（schema_version %d，本程式支援 %d）。本程式不認識的設定會被保留，但不會生效。",
+		"config_corrupt_message":                   "設定檔無法讀取，現在使用預設設定：%v\n\n原檔案已備份為：\n%s",
 		"status_ready":                             "準備就緒。請選擇一個選項。",
 		"tab_aircraft":                             "飛機",
 		"tab_liveries":                             "塗裝",
@@ -1272,7 +1379,7 @@ var translations = map[string]map[string]string{
 		"liveries_selected_status":                 "已選擇 %d 個塗裝。",
 		"no_livery_selected_status":                "未選擇塗裝。請勾選以開始。",
 		"update_tab_title":                         "更新此應用程式",
-		"update_tab_desc":                          "這將會下載此安裝程式的最新版本（.exe 檔案）。您需要關閉此應用程式並手動執行新程式。",
+		"update_tab_desc":                          "這將會在目前程式旁下載此安裝程式的最新版本，取代目前程式並自動重新啟動。如果新版本無法啟動，將還原目前版本。",
 		"update_tab_warning":                       "注意：此檔案未經數位簽章，可能會被防毒軟體標記。這是誤報。",
 		"download_latest_button":                   "下載最新應用程式版本",
 		"settings_tab_title":                       "應用程式設定",
@@ -1294,24 +1401,121 @@ var translations = map[string]map[string]string{
 		"find_aircraft_dir_error":                  "未能找到有效的 AeroGennis A330 目錄。請先安裝飛機，或在設定中手動指定路徑。",
 		"status_downloading_update":                "正在下載新版應用程式...",
 		"download_update_error":                    "下載更新失敗",
-		"update_download_complete_status":          "更新下載成功！",
-		"update_download_complete_title":           "下載完成",
-		"update_download_complete_message":         "新版本已儲存至：\n%s\n請關閉此應用程式並執行新程式。",
+		"update_confirm_title":                     "更新安裝程式",
+		"update_confirm_message":                   "安裝程式將下載新版本、取代自身並重新啟動。是否繼續？",
+		"status_installing_update":                 "正在取代目前程式...",
+		"status_restarting":                        "正在啟動新版本...",
+		"operation_warning_status":                 "警告：%v",
+		"livery_requires_newer_aircraft_title":     "需要更新飛機",
+		"livery_requires_newer_aircraft_message":   "以下塗裝需要比已安裝的 %s 更新的飛機版本，將被略過：\n- %s",
+		"livery_min_aircraft_line":                 "%s（需要 %s）",
+		"livery_requires_newer_aircraft_error":     "需要飛機版本 %s 或更高（已安裝：%s）",
+		"update_unverified_error":                  "版本清單沒有公布該版本的校驗值，無法安全地安裝",
+		"update_unverified_confirm_message":        "版本清單沒有公布該版本的校驗值，下載的程式無法校驗。只有在信任下載來源時才應安裝。仍要更新嗎？",
+		"download_unverified_status":               "下載完成，但發布者沒有公布校驗值，檔案未經校驗",
+		"aircraft_unverified_warning":              "警告：版本清單沒有公布飛機包的校驗值，下載的飛機包未經校驗。",
+		"aircraft_manifest_unavailable_warning":    "警告：無法下載版本清單（%s），飛機包未經校驗。",
+		"concurrency_adjusted_status":              "同時下載數調整為 %d",
+		"update_install_error":                     "安裝更新失敗",
+		"update_rollback_error":                    "新版本無法啟動，已還原舊版本",
 		"download_progress_label":                  "下載中... %.2f / %.2f MB (%.2f MB/s)",
 		"download_no_progress":                     "警告：內容長度未知。將不會顯示進度。",
 		"extract_progress_label":                   "正在解壓縮: %s",
 		"install_selected_liveries_button":         "安裝所選塗裝",
+		"livery_search_placeholder":                "搜尋名稱、航空公司、註冊號或 ICAO 代碼",
+		"filter_all_types":                         "真實和虛構",
+		"filter_real":                              "僅真實",
+		"filter_fictional":                         "僅虛構",
+		"filter_all_install_states":                "全部安裝狀態",
+		"filter_installed":                         "已安裝",
+		"filter_not_installed":                     "未安裝",
+		"filter_updates_only":                      "僅有更新",
+		"group_by_airline":                         "按航空公司分組",
+		"sort_by_name":                             "按名稱排序",
+		"sort_by_airline":                          "按航空公司排序",
+		"sort_by_registration":                     "按註冊號排序",
+		"select_shown_button":                      "選擇顯示的塗裝",
+		"clear_selection_button":                   "清除選擇",
+		"catalog_count_label":                      "顯示 %d / %d",
+		"livery_reconcile_error":                   "無法對照塗裝資料夾：%v",
+		"airline_group_label":                      "%s（%d）",
+		"no_airline_group":                         "無航空公司",
+		"gallery_view_check":                       "畫廊",
+		"preview_none_label":                       "點擊塗裝查看預覽",
+		"preview_airline_label":                    "航空公司：%s",
+		"preview_registration_label":               "註冊號：%s",
+		"preview_author_label":                     "作者：%s",
+		"preview_real_label":                       "真實塗裝",
+		"preview_fictional_label":                  "虛構塗裝",
+		"badge_installed":                          "已安裝",
+		"badge_outdated":                           "有更新",
+		"badge_not_installed":                      "未安裝",
+		"preview_version_label":                    "版本：%s",
+		"preview_installed_label":                  "安裝在：%s",
+		"preview_outdated_label":                   "已安裝，目錄中有更新的包",
+		"preview_outdated_version_label":           "已安裝的版本 %s 已過期",
+		"update_outdated_button":                   "更新過期塗裝（%d）",
+		"update_outdated_title":                    "更新過期塗裝",
+		"update_outdated_confirm_message":          "要重新安裝這 %d 個過期的塗裝嗎？\n\n- %s",
+		"no_outdated_liveries_message":             "已安裝的塗裝都是最新的。",
+		"uninstall_livery_entry":                   "%s（%s）",
 		"update_livery_list_button":                "更新塗裝列表",
 		"uninstall_liveries_button":                "卸載塗裝...",
-		"status_updating_livery_list":              "正在下載最新的塗装列表...",
 		"livery_list_download_error":               "下載塗裝列表失敗",
 		"livery_list_read_error":                   "讀取下載的塗裝列表資料失敗",
 		"livery_list_save_error":                   "儲存塗裝列表檔案失败",
+		"catalog_state_save_error":                 "塗裝列表已重新整理，但無法儲存重新整理記錄",
 		"update_success_title":                     "已更新",
 		"livery_list_update_success":               "塗裝列表已成功更新並重新載入！",
+		"livery_list_not_modified":                 "塗裝列表已是最新。",
+		"livery_list_invalid_error":                "下載的塗裝列表無效，已保留目前列表",
+		"livery_list_empty_error":                  "下載的塗裝列表中沒有塗裝，已保留目前列表",
+		"catalog_diff_none":                        "沒有新增、刪除或變化的塗裝。",
+		"catalog_diff_added":                       "新增（%d）：",
+		"catalog_diff_removed":                     "刪除（%d）：",
+		"catalog_diff_changed":                     "變化（%d）：",
+		"catalog_diff_more":                        "……以及另外 %d 個",
+		"catalog_last_refreshed_label":             "塗裝列表上次重新整理：%s",
+		"catalog_never_refreshed_label":            "塗裝列表尚未重新整理",
+		"status_updating_catalog_source":           "正在從 %s 更新塗裝列表...",
+		"catalog_source_failures":                  "以下來源重新整理失敗，繼續使用上次的列表 (%d)：",
+		"preview_source_label":                     "來源：%s",
+		"catalog_sources_label":                    "塗裝列表來源",
+		"catalog_sources_hint":                     "所有啟用的來源中的塗裝會合併顯示。多個來源包含同一塗裝時，使用優先順序最高的來源。新增的遠端來源會在下次更新塗裝列表時下載。",
+		"catalog_source_add_button":                "新增來源",
+		"catalog_source_edit_title":                "編輯來源",
+		"catalog_source_name_label":                "名稱",
+		"catalog_source_location_label":            "位置",
+		"catalog_source_location_placeholder":      "https://example.com/liveries.json 或本機檔案/資料夾",
+		"catalog_source_priority_label":            "優先順序",
+		"catalog_source_enabled_check":             "啟用",
+		"catalog_source_info":                      "優先順序 %d · %s",
+		"catalog_source_delete_title":              "刪除來源",
+		"catalog_source_delete_message":            "確定刪除塗裝列表來源「%s」嗎？",
+		"catalog_source_name_error":                "請輸入來源名稱",
+		"catalog_source_duplicate_error":           "已存在名為「%s」的來源",
+		"catalog_source_https_error":               "遠端來源必須使用 https:// 連結",
+		"catalog_source_location_error":            "找不到檔案或資料夾：%s",
+		"catalog_source_priority_error":            "優先順序必須是整數",
+		"catalog_source_last_error":                "至少需要保留一個塗裝列表來源",
 		"livery_list_load_fail":                    "無法載入塗装列表。請嘗試更新它。",
 		"batch_download_progress_label":            "下載中 (%d/%d): %s",
-		"batch_install_complete_status":            "%d 個塗裝處理完畢。",
+		"queue_title":                              "安裝佇列",
+		"queue_start_button":                       "開始佇列",
+		"queue_clear_finished_button":              "清除已結束",
+		"queue_row_label":                          "%s（%s）",
+		"queue_pending_status":                     "佇列中有 %d 個塗裝等待安裝。在塗裝頁點擊「開始佇列」繼續。",
+		"job_state_queued":                         "等待中",
+		"job_state_running":                        "安裝中",
+		"job_state_paused":                         "已暫停",
+		"job_state_done":                           "已安裝",
+		"job_state_failed":                         "失敗",
+		"batch_install_report_status":              "塗裝安裝成功 %d 個，失敗 %d 個。",
+		"report_title":                             "安裝報告",
+		"report_succeeded_label":                   "安裝成功（%d）：",
+		"report_failed_label":                      "安裝失敗（%d）：",
+		"queue_retry_failed_button":                "重試失敗項",
+		"close_button":                             "關閉",
 		"batch_install_complete_message":           "%d 個選中的塗裝已處理並安裝。",
 		"scan_liveries_dir_error":                  "掃描塗裝目錄失敗",
 		"no_installed_liveries_title":              "未找到塗裝",
@@ -1319,6 +1523,8 @@ var translations = map[string]map[string]string{
 		"uninstall_dialog_title":                   "選擇要卸載的塗裝",
 		"confirm_button":                           "確認",
 		"cancel_button":                            "取消",
+		"status_cancelling":                        "正在取消...",
+		"operation_cancelled_status":               "已取消。未完成的下載和解壓檔案已刪除。",
 		"uninstall_final_confirm_title":            "永久刪除",
 		"uninstall_final_confirm_message":          "您確定要永久刪除這 %d 個選中的資料夾嗎？\n\n- %s",
 		"uninstall_complete_title":                 "卸載報告",
@@ -1332,12 +1538,41 @@ var translations = map[string]map[string]string{
 		"uninstall_aircraft_error":                 "卸載機模失敗",
 		"uninstall_aircraft_success_message":       "AeroGennis A330-300 已成功卸載。",
 		"self_uninstall_button":                    "卸載此應用程式",
-		"self_uninstall_warning":                   "警告：這將移除安裝程式本身（.exe）、其設定檔和塗裝列表檔案。您需要重新下載才能再次使用本程式。",
+		"self_uninstall_warning":                   "警告：這將移除安裝程式本身、其設定檔和塗裝列表檔案。您需要重新下載才能再次使用本程式。",
 		"self_uninstall_confirm_title":             "確認卸載應用程式",
 		"self_uninstall_confirm_message":           "您確定要從您的電腦上完全移除此應用程式及其相關檔案嗎？",
+		"download_retry_status":                    "第 %d/%d 次嘗試失敗：%v。%.0f 秒後重試...",
+		"attempt_history_label":                    "嘗試記錄：",
+		"attempt_history_line":                     "第 %d 次：%v",
+		"livery_download_failed_status":            "下載 %s 失敗：%v",
+		"integrity_sha256_mismatch_error":          "完整性校驗失敗：下載檔案的 SHA-256 與發佈值不一致。\n預期：%s\n實際：%s\n檔案已捨棄，未安裝任何內容。",
+		"integrity_size_mismatch_error":            "完整性校驗失敗：預期 %d 位元組，實際下載 %d 位元組。\n檔案已捨棄，未安裝任何內容。",
+		"status_swapping_install":                  "正在校驗並替換舊版本...",
+		"install_failed_status":                    "安裝失敗。",
+		"install_verify_error":                     "無法安裝新的飛機檔案",
+		"install_rolled_back_message":              "已恢復您先前的安裝，未做任何變更。",
+		"verify_aircraft_button":                   "校驗已安裝檔案",
+		"verify_title":                             "檔案校驗",
+		"verify_no_receipt_message":                "找不到此飛機的安裝記錄。請使用此版本的安裝程式重新安裝一次以啟用校驗。",
+		"verify_progress_label":                    "正在校驗檔案... %d / %d",
+		"verify_ok_message":                        "全部 %d 個已安裝檔案均存在且未被修改。",
+		"verify_problems_message":                  "%d 個檔案遺失或已被修改：\n%s\n\n請重新安裝飛機以修復。",
+		"verify_missing_file":                      "遺失：%s",
+		"verify_changed_file":                      "已修改：%s",
+		"installed_version_label":                  "已安裝版本：%s",
+		"latest_version_label":                     "最新可用版本：%s",
+		"published_on_label":                       "發佈於 %s",
+		"version_unknown":                          "未知",
+		"version_checking":                         "正在檢查...",
+		"version_unavailable":                      "無法檢查",
+		"update_available_label":                   "飛機有可用更新。",
+		"up_to_date_label":                         "已安裝的飛機是最新版本。",
+		"update_aircraft_button":                   "更新 AeroGennis A330-300",
+		"current_app_version_label":                "目前版本：%s",
+		"app_update_available_banner":              "安裝程式有新版本可用：%s（目前為 %s）。",
 	},
 	"fr-FR": {
-		"window_title":                             "Installeur AeroGennis A330-300 - v2025.8.3.20-Preview",
+		"window_title":                             "Installeur AeroGennis A330-300 - v%s",
 		"reinstall_button":                         "Vérifier les mises à jour / Réinstaller",
 		"aircraft_installed_title":                 "Installation Détectée",
 		"aircraft_installed_desc":                  "L'application a détecté que l'AeroGennis A330-300 est déjà installé. Vous pouvez vérifier les mises à jour ou le réinstaller si nécessaire.",
@@ -1348,12 +1583,48 @@ var translations = map[string]map[string]string{
 		"save_success_title":                       "Enregistré",
 		"save_manual_path_success":                 "Le chemin manuel a été enregistré avec succès. L'application a été actualisée.",
 		"setup_welcome":                            "Bienvenue ! Veuillez sélectionner votre répertoire racine de X-Plane 12 pour commencer.",
+		"discovered_installs_label":                "Installations de X-Plane 12 détectées :",
+		"discovered_none_label":                    "Aucune installation de X-Plane 12 n'a été détectée automatiquement. Veuillez saisir ou parcourir le répertoire.",
+		"profiles_label":                           "Profils X-Plane",
+		"download_settings_label":                  "Téléchargements",
+		"concurrency_label":                        "Téléchargements simultanés",
+		"adaptive_concurrency_check":               "Ajuster automatiquement selon le débit et les erreurs",
+		"bandwidth_limit_label":                    "Limite de débit (Ko/s, 0 = illimité)",
+		"bandwidth_limit_error":                    "La limite de débit doit être un nombre entier de Ko/s, supérieur ou égal à 0.",
+		"save_download_settings_button":            "Enregistrer les paramètres de téléchargement",
+		"download_settings_saved":                  "Paramètres de téléchargement enregistrés. La limite de débit s'applique immédiatement, le nombre de téléchargements simultanés au prochain lancement de la file.",
+		"bandwidth_schedule_label":                 "Limites selon l'heure (prioritaires sur la limite ci-dessus ; la première règle correspondante s'applique) :",
+		"bandwidth_no_rules_label":                 "Aucune règle horaire.",
+		"bandwidth_add_rule_button":                "Ajouter une règle horaire",
+		"bandwidth_rule_unlimited":                 "%s – %s : illimité",
+		"bandwidth_rule_limited":                   "%s – %s : %d Ko/s",
+		"bandwidth_rule_start_label":               "De (HH:MM)",
+		"bandwidth_rule_end_label":                 "Jusqu'à (HH:MM)",
+		"bandwidth_rule_time_error":                "Saisissez les heures au format HH:MM sur 24 heures, par exemple 22:00.",
+		"new_profile_button":                       "Nouveau profil",
+		"delete_profile_button":                    "Supprimer le profil actuel",
+		"new_profile_title":                        "Nouveau profil",
+		"profile_name_label":                       "Nom du profil",
+		"profile_name_error":                       "Impossible de créer le profil",
+		"delete_profile_confirm_title":             "Supprimer le profil",
+		"delete_profile_confirm_message":           "Supprimer le profil '%s' ? Les fichiers installés ne sont pas supprimés.",
+		"install_targets_title":                    "Choisir les profils",
+		"install_targets_message":                  "Installer dans les profils suivants :",
+		"status_installing_for_profile":            "Installation pour le profil '%s'...",
 		"path_placeholder":                         "Entrez ou parcourez jusqu'à votre répertoire racine de X-Plane 12...",
 		"browse_button":                            "Parcourir...",
 		"save_continue_button":                     "Enregistrer et Continuer",
 		"invalid_xp_path_error":                    "Le répertoire sélectionné n'est pas une installation valide de X-Plane 12.",
 		"missing_items_label":                      "Éléments manquants :",
 		"save_config_error":                        "Échec de la sauvegarde de la configuration",
+		"config_load_error":                        "Impossible de lire la configuration",
+		"queue_load_error":                         "Impossible de lire la file d'installation enregistrée",
+		"preview_cache_error":                      "Impossible d'ouvrir le cache des aperçus de livrées, seuls les aperçus des livrées installées sont affichés",
+		"config_warnings_title":                    "Certains réglages ont été ignorés",
+		"config_rule_ignored_warning":              "La règle de vitesse de téléchargement %s-%s a été ignorée : %v",
+		"config_insecure_source_warning":           "La source de liste de livrées %s a été ignorée car %s n'utilise pas https",
+		"config_newer_schema_warning":              "%s a été enregistré par une version plus récente de l'installateur (schema_version %d, cette version prend en charge %d). Les réglages inconnus sont conservés mais ne sont pas utilisés.",
+		"config_corrupt_message":                   "Le fichier de configuration n'a pas pu être lu, les paramètres par défaut sont utilisés : %v\n\nLe fichier d'origine a été conservé sous :\n%s",
 		"status_ready":                             "Prêt. Sélectionnez une option.",
 		"tab_aircraft":                             "Avion",
 		"tab_liveries":                             "Livrées",
@@ -1367,7 +1638,7 @@ var translations = map[string]map[string]string{
 		"liveries_selected_status":                 "%d livrées sélectionnées.",
 		"no_livery_selected_status":                "Aucune livrée sélectionnée. Cochez une case pour commencer.",
 		"update_tab_title":                         "Mettre à Jour Cette Application",
-		"update_tab_desc":                          "Ceci téléchargera la dernière version de cet installeur (en tant que fichier .exe). Vous devrez fermer cette application et exécuter la nouvelle manuellement.",
+		"update_tab_desc":                          "Ceci téléchargera la dernière version de cet installeur à côté du programme actuel, le remplacera et redémarrera automatiquement. Si la nouvelle version ne démarre pas, la version actuelle est restaurée.",
 		"update_tab_warning":                       "Note : Ce fichier n'est pas signé numériquement et peut être signalé par un logiciel antivirus. C'est un faux positif.",
 		"download_latest_button":                   "Télécharger la Dernière Version de l'Application",
 		"settings_tab_title":                       "Paramètres de l'Application",
@@ -1389,24 +1660,121 @@ var translations = map[string]map[string]string{
 		"find_aircraft_dir_error":                  "Impossible de trouver un répertoire valide pour l'AeroGennis A330. Veuillez d'abord installer l'avion ou définir le chemin manuellement dans les Paramètres.",
 		"status_downloading_update":                "Téléchargement de la nouvelle version de l'application...",
 		"download_update_error":                    "Échec du téléchargement de la mise à jour",
-		"update_download_complete_status":          "Mise à jour téléchargée avec succès !",
-		"update_download_complete_title":           "Téléchargement Terminé",
-		"update_download_complete_message":         "Nouvelle version enregistrée dans :\n%s\nVeuillez fermer cette application et exécuter la nouvelle.",
+		"update_confirm_title":                     "Mettre à jour l'installeur",
+		"update_confirm_message":                   "L'installeur va télécharger la nouvelle version, se remplacer et redémarrer. Continuer ?",
+		"status_installing_update":                 "Remplacement du programme actuel...",
+		"status_restarting":                        "Démarrage de la nouvelle version...",
+		"operation_warning_status":                 "Avertissement : %v",
+		"livery_requires_newer_aircraft_title":     "Mise à jour de l'avion requise",
+		"livery_requires_newer_aircraft_message":   "Ces livrées nécessitent une version de l'avion plus récente que la version installée %s et seront ignorées :\n- %s",
+		"livery_min_aircraft_line":                 "%s (nécessite %s)",
+		"livery_requires_newer_aircraft_error":     "nécessite la version %s de l'avion ou ultérieure (installée : %s)",
+		"update_unverified_error":                  "le manifeste de version ne publie pas de somme de contrôle pour cette version, elle ne peut pas être installée en toute sécurité",
+		"update_unverified_confirm_message":        "Le manifeste des versions ne publie pas de somme de contrôle pour cette version, le téléchargement ne peut donc pas être vérifié. Ne l'installez que si vous faites confiance à la source. Mettre à jour quand même ?",
+		"download_unverified_status":               "Téléchargement terminé, mais aucune somme de contrôle n'est publiée : le fichier n'a pas pu être vérifié",
+		"aircraft_unverified_warning":              "Attention : le manifeste de version ne publie pas de somme de contrôle pour le paquet de l'avion, le paquet téléchargé n'a donc pas pu être vérifié.",
+		"aircraft_manifest_unavailable_warning":    "Avertissement : le manifeste des versions n'a pas pu être téléchargé (%s), le paquet de l'avion n'a donc pas pu être vérifié.",
+		"concurrency_adjusted_status":              "Téléchargements simultanés ajustés à %d",
+		"update_install_error":                     "Échec de l'installation de la mise à jour",
+		"update_rollback_error":                    "La nouvelle version n'a pas pu démarrer et la version précédente a été restaurée",
 		"download_progress_label":                  "Téléchargement... %.2f / %.2f Mo (%.2f Mo/s)",
 		"download_no_progress":                     "Avertissement : Longueur du contenu inconnue. La progression ne sera pas affichée.",
 		"extract_progress_label":                   "Extraction : %s",
 		"install_selected_liveries_button":         "Installer les Livrées Sélectionnées",
+		"livery_search_placeholder":                "Rechercher nom, compagnie, immatriculation ou code OACI",
+		"filter_all_types":                         "Réelles et fictives",
+		"filter_real":                              "Réelles uniquement",
+		"filter_fictional":                         "Fictives uniquement",
+		"filter_all_install_states":                "Installées ou non",
+		"filter_installed":                         "Installées",
+		"filter_not_installed":                     "Non installées",
+		"filter_updates_only":                      "Mises à jour uniquement",
+		"group_by_airline":                         "Grouper par compagnie",
+		"sort_by_name":                             "Trier par nom",
+		"sort_by_airline":                          "Trier par compagnie",
+		"sort_by_registration":                     "Trier par immatriculation",
+		"select_shown_button":                      "Sélectionner l'affichage",
+		"clear_selection_button":                   "Effacer la sélection",
+		"catalog_count_label":                      "%d sur %d affichées",
+		"livery_reconcile_error":                   "Impossible de vérifier les dossiers de livrées : %v",
+		"airline_group_label":                      "%s (%d)",
+		"no_airline_group":                         "Sans compagnie",
+		"gallery_view_check":                       "Galerie",
+		"preview_none_label":                       "Cliquez sur une livrée pour l'aperçu",
+		"preview_airline_label":                    "Compagnie : %s",
+		"preview_registration_label":               "Immatriculation : %s",
+		"preview_author_label":                     "Auteur : %s",
+		"preview_real_label":                       "Livrée réelle",
+		"preview_fictional_label":                  "Livrée fictive",
+		"badge_installed":                          "Installée",
+		"badge_outdated":                           "Mise à jour",
+		"badge_not_installed":                      "Non installée",
+		"preview_version_label":                    "Version : %s",
+		"preview_installed_label":                  "Installée dans : %s",
+		"preview_outdated_label":                   "Installée, mais le catalogue contient un paquet plus récent",
+		"preview_outdated_version_label":           "La version installée %s est obsolète",
+		"update_outdated_button":                   "Mettre à jour les obsolètes (%d)",
+		"update_outdated_title":                    "Mettre à jour les livrées obsolètes",
+		"update_outdated_confirm_message":          "Réinstaller ces %d livrées obsolètes ?\n\n- %s",
+		"no_outdated_liveries_message":             "Toutes les livrées installées sont à jour.",
+		"uninstall_livery_entry":                   "%s (%s)",
 		"update_livery_list_button":                "Mettre à Jour la Liste",
 		"uninstall_liveries_button":                "Désinstaller des Livrées...",
-		"status_updating_livery_list":              "Téléchargement de la dernière liste de livrées...",
 		"livery_list_download_error":               "Échec du téléchargement de la liste de livrées",
 		"livery_list_read_error":                   "Échec de la lecture des données de la liste de livrées",
 		"livery_list_save_error":                   "Échec de l'enregistrement du fichier de la liste de livrées",
+		"catalog_state_save_error":                 "La liste a été actualisée mais l'historique d'actualisation n'a pas pu être enregistré",
 		"update_success_title":                     "Mis à Jour",
 		"livery_list_update_success":               "La liste de livrées a été mise à jour et rechargée avec succès !",
+		"livery_list_not_modified":                 "La liste des livrées est déjà à jour.",
+		"livery_list_invalid_error":                "La liste des livrées téléchargée est invalide, la liste actuelle a été conservée",
+		"livery_list_empty_error":                  "La liste des livrées téléchargée ne contient aucune livrée, la liste actuelle a été conservée",
+		"catalog_diff_none":                        "Aucune livrée ajoutée, supprimée ou modifiée.",
+		"catalog_diff_added":                       "Ajoutées (%d) :",
+		"catalog_diff_removed":                     "Supprimées (%d) :",
+		"catalog_diff_changed":                     "Modifiées (%d) :",
+		"catalog_diff_more":                        "… et %d de plus",
+		"catalog_last_refreshed_label":             "Liste des livrées actualisée le : %s",
+		"catalog_never_refreshed_label":            "La liste des livrées n'a pas encore été actualisée",
+		"status_updating_catalog_source":           "Mise à jour de la liste des livrées depuis %s...",
+		"catalog_source_failures":                  "Ces sources n'ont pas pu être actualisées et gardent leur liste précédente (%d) :",
+		"preview_source_label":                     "Source : %s",
+		"catalog_sources_label":                    "Sources de la liste des livrées",
+		"catalog_sources_hint":                     "Les livrées de toutes les sources activées sont fusionnées. Si plusieurs sources proposent la même livrée, celle de plus haute priorité est utilisée. Les nouvelles sources distantes sont téléchargées lors de la prochaine mise à jour de la liste.",
+		"catalog_source_add_button":                "Ajouter une source",
+		"catalog_source_edit_title":                "Modifier la source",
+		"catalog_source_name_label":                "Nom",
+		"catalog_source_location_label":            "Emplacement",
+		"catalog_source_location_placeholder":      "https://example.com/liveries.json ou un fichier/dossier local",
+		"catalog_source_priority_label":            "Priorité",
+		"catalog_source_enabled_check":             "Activée",
+		"catalog_source_info":                      "Priorité %d · %s",
+		"catalog_source_delete_title":              "Supprimer la source",
+		"catalog_source_delete_message":            "Supprimer la source de livrées « %s » ?",
+		"catalog_source_name_error":                "Veuillez saisir un nom pour la source",
+		"catalog_source_duplicate_error":           "Une source nommée « %s » existe déjà",
+		"catalog_source_https_error":               "Les sources distantes doivent utiliser un lien https://",
+		"catalog_source_location_error":            "Fichier ou dossier introuvable : %s",
+		"catalog_source_priority_error":            "La priorité doit être un nombre entier",
+		"catalog_source_last_error":                "Au moins une source de livrées est nécessaire",
 		"livery_list_load_fail":                    "Impossible de charger la liste de livrées. Veuillez essayer de la mettre à jour.",
 		"batch_download_progress_label":            "Téléchargement (%d/%d) : %s",
-		"batch_install_complete_status":            "%d livrées traitées.",
+		"queue_title":                              "File d'installation",
+		"queue_start_button":                       "Démarrer la file",
+		"queue_clear_finished_button":              "Effacer les terminés",
+		"queue_row_label":                          "%s (%s)",
+		"queue_pending_status":                     "%d livrées attendent dans la file d'installation. Cliquez sur Démarrer la file dans l'onglet Livrées pour continuer.",
+		"job_state_queued":                         "en attente",
+		"job_state_running":                        "installation",
+		"job_state_paused":                         "en pause",
+		"job_state_done":                           "installée",
+		"job_state_failed":                         "échec",
+		"batch_install_report_status":              "Livrées installées : %d, échecs : %d.",
+		"report_title":                             "Rapport d'installation",
+		"report_succeeded_label":                   "Installées (%d) :",
+		"report_failed_label":                      "Échecs (%d) :",
+		"queue_retry_failed_button":                "Réessayer les échecs",
+		"close_button":                             "Fermer",
 		"batch_install_complete_message":           "%d livrées sélectionnées ont été traitées et installées.",
 		"scan_liveries_dir_error":                  "Échec de l'analyse du répertoire des livrées",
 		"no_installed_liveries_title":              "Aucune Livrée Trouvée",
@@ -1414,6 +1782,8 @@ var translations = map[string]map[string]string{
 		"uninstall_dialog_title":                   "Sélectionner les Livrées à Désinstaller",
 		"confirm_button":                           "Confirmer",
 		"cancel_button":                            "Annuler",
+		"status_cancelling":                        "Annulation...",
+		"operation_cancelled_status":               "Annulé. Les téléchargements et fichiers extraits incomplets ont été supprimés.",
 		"uninstall_final_confirm_title":            "Suppression Permanente",
 		"uninstall_final_confirm_message":          "Êtes-vous sûr de vouloir supprimer définitivement ces %d dossiers sélectionnés ?\n\n- %s",
 		"uninstall_complete_title":                 "Rapport de Désinstallation",
@@ -1430,9 +1800,38 @@ var translations = map[string]map[string]string{
 		"self_uninstall_warning":                   "AVERTISSEMENT : Ceci supprimera l'exécutable de l'installeur lui-même, ainsi que ses fichiers de configuration et de liste de livrées. Vous devrez le retélécharger pour l'utiliser à nouveau.",
 		"self_uninstall_confirm_title":             "Confirmer la Désinstallation de l'Application",
 		"self_uninstall_confirm_message":           "Êtes-vous sûr de vouloir supprimer complètement cette application et ses fichiers associés de votre ordinateur ?",
+		"download_retry_status":                    "Tentative %d/%d échouée : %v. Nouvel essai dans %.0f s...",
+		"attempt_history_label":                    "Historique des tentatives :",
+		"attempt_history_line":                     "Tentative %d : %v",
+		"livery_download_failed_status":            "Échec du téléchargement de %s : %v",
+		"integrity_sha256_mismatch_error":          "Échec de la vérification d'intégrité : le SHA-256 du fichier téléchargé ne correspond pas à la valeur publiée.\nAttendu : %s\nObtenu : %s\nLe fichier a été supprimé et rien n'a été installé.",
+		"integrity_size_mismatch_error":            "Échec de la vérification d'intégrité : %d octets attendus mais le téléchargement en contient %d.\nLe fichier a été supprimé et rien n'a été installé.",
+		"status_swapping_install":                  "Vérification et remplacement de l'installation précédente...",
+		"install_failed_status":                    "Échec de l'installation.",
+		"install_verify_error":                     "Impossible d'installer les nouveaux fichiers de l'avion",
+		"install_rolled_back_message":              "Votre installation précédente a été restaurée et n'a pas été modifiée.",
+		"verify_aircraft_button":                   "Vérifier les fichiers installés",
+		"verify_title":                             "Vérification des fichiers",
+		"verify_no_receipt_message":                "Aucun enregistrement d'installation n'a été trouvé pour cet avion. Réinstallez-le une fois avec cette version de l'installateur pour activer la vérification.",
+		"verify_progress_label":                    "Vérification des fichiers... %d / %d",
+		"verify_ok_message":                        "Les %d fichiers installés sont présents et inchangés.",
+		"verify_problems_message":                  "%d fichiers sont manquants ou modifiés :\n%s\n\nRéinstallez l'avion pour les réparer.",
+		"verify_missing_file":                      "Manquant : %s",
+		"verify_changed_file":                      "Modifié : %s",
+		"installed_version_label":                  "Version installée : %s",
+		"latest_version_label":                     "Dernière version disponible : %s",
+		"published_on_label":                       "publiée le %s",
+		"version_unknown":                          "inconnue",
+		"version_checking":                         "vérification...",
+		"version_unavailable":                      "impossible à vérifier",
+		"update_available_label":                   "Une mise à jour de l'avion est disponible.",
+		"up_to_date_label":                         "L'avion installé est à jour.",
+		"update_aircraft_button":                   "Mettre à jour AeroGennis A330-300",
+		"current_app_version_label":                "Version actuelle : %s",
+		"app_update_available_banner":              "Une nouvelle version de cet installateur est disponible : %s (vous avez %s).",
 	},
 	"ru-RU": {
-		"window_title":                             "Установщик AeroGennis A330-300 - v2025.8.3.20-Preview",
+		"window_title":                             "Установщик AeroGennis A330-300 - v%s",
 		"reinstall_button":                         "Проверить обновления / Переустановить",
 		"aircraft_installed_title":                 "Обнаружена Установка",
 		"aircraft_installed_desc":                  "Приложение обнаружило, что AeroGennis A330-300 уже установлен. Вы можете проверить наличие обновлений или переустановить при необходимости.",
@@ -1443,12 +1842,48 @@ var translations = map[string]map[string]string{
 		"save_success_title":                       "Сохранено",
 		"save_manual_path_success":                 "Путь, указанный вручную, успешно сохранен. Приложение было обновлено.",
 		"setup_welcome":                            "Добро пожаловать! Пожалуйста, выберите корневой каталог X-Plane 12, чтобы начать.",
+		"discovered_installs_label":                "Обнаруженные установки X-Plane 12:",
+		"discovered_none_label":                    "Установка X-Plane 12 не обнаружена автоматически. Введите путь или выберите каталог.",
+		"profiles_label":                           "Профили X-Plane",
+		"download_settings_label":                  "Загрузки",
+		"concurrency_label":                        "Одновременных загрузок",
+		"adaptive_concurrency_check":               "Подбирать автоматически по скорости и ошибкам",
+		"bandwidth_limit_label":                    "Ограничение скорости (КБ/с, 0 — без ограничения)",
+		"bandwidth_limit_error":                    "Ограничение скорости должно быть целым числом КБ/с не меньше 0.",
+		"save_download_settings_button":            "Сохранить настройки загрузки",
+		"download_settings_saved":                  "Настройки загрузки сохранены. Ограничение скорости действует сразу, число одновременных загрузок — со следующего запуска очереди.",
+		"bandwidth_schedule_label":                 "Ограничения по времени суток (важнее ограничения выше; действует первое подходящее правило):",
+		"bandwidth_no_rules_label":                 "Правил по времени нет.",
+		"bandwidth_add_rule_button":                "Добавить правило",
+		"bandwidth_rule_unlimited":                 "%s – %s: без ограничения",
+		"bandwidth_rule_limited":                   "%s – %s: %d КБ/с",
+		"bandwidth_rule_start_label":               "С (ЧЧ:ММ)",
+		"bandwidth_rule_end_label":                 "До (ЧЧ:ММ)",
+		"bandwidth_rule_time_error":                "Введите время в 24-часовом формате ЧЧ:ММ, например 22:00.",
+		"new_profile_button":                       "Новый профиль",
+		"delete_profile_button":                    "Удалить текущий профиль",
+		"new_profile_title":                        "Новый профиль",
+		"profile_name_label":                       "Имя профиля",
+		"profile_name_error":                       "Не удалось создать профиль",
+		"delete_profile_confirm_title":             "Удаление профиля",
+		"delete_profile_confirm_message":           "Удалить профиль '%s'? Установленные файлы не будут удалены.",
+		"install_targets_title":                    "Выбор профилей",
+		"install_targets_message":                  "Установить в следующие профили:",
+		"status_installing_for_profile":            "Установка для профиля '%s'...",
 		"path_placeholder":                         "Введите или выберите корневой каталог X-Plane 12...",
 		"browse_button":                            "Обзор...",
 		"save_continue_button":                     "Сохранить и Продолжить",
 		"invalid_xp_path_error":                    "Выбранный каталог не является действительной установкой X-Plane 12.",
 		"missing_items_label":                      "Отсутствующие элементы:",
 		"save_config_error":                        "Не удалось сохранить конфигурацию",
+		"config_load_error":                        "Не удалось прочитать настройки",
+		"queue_load_error":                         "Не удалось прочитать сохранённую очередь установки",
+		"preview_cache_error":                      "Не удалось открыть кэш превью ливрей, показываются только превью установленных ливрей",
+		"config_warnings_title":                    "Некоторые настройки проигнорированы",
+		"config_rule_ignored_warning":              "Правило скорости загрузки %s-%s проигнорировано: %v",
+		"config_insecure_source_warning":           "Источник списка ливрей %s пропущен: %s не использует https",
+		"config_newer_schema_warning":              "%s сохранён более новой версией установщика (schema_version %d, эта версия поддерживает %d). Неизвестные настройки сохраняются, но не используются.",
+		"config_corrupt_message":                   "Не удалось прочитать файл настроек, используются настройки по умолчанию: %v\n\nИсходный файл сохранён как:\n%s",
 		"status_ready":                             "Готово. Выберите действие.",
 		"tab_aircraft":                             "Самолёт",
 		"tab_liveries":                             "Ливреи",
@@ -1462,7 +1897,7 @@ var translations = map[string]map[string]string{
 		"liveries_selected_status":                 "Выбрано %d ливрей.",
 		"no_livery_selected_status":                "Ливрея не выбрана. Поставьте галочку, чтобы начать.",
 		"update_tab_title":                         "Обновить Это Приложение",
-		"update_tab_desc":                          "Будет загружена последняя версия этого установщика (как .exe файл). Вам нужно будет закрыть это приложение и запустить новое вручную.",
+		"update_tab_desc":                          "Последняя версия этого установщика будет загружена рядом с текущей программой, заменит её и автоматически перезапустится. Если новая версия не запустится, текущая версия будет восстановлена.",
 		"update_tab_warning":                       "Примечание: Этот файл не имеет цифровой подписи и может быть помечен антивирусным ПО. Это ложное срабатывание.",
 		"download_latest_button":                   "Загрузить Последнюю Версию Приложения",
 		"settings_tab_title":                       "Настройки Приложения",
@@ -1484,24 +1919,121 @@ var translations = map[string]map[string]string{
 		"find_aircraft_dir_error":                  "Не удалось найти действительный каталог AeroGennis A330. Пожалуйста, сначала установите самолёт или задайте путь вручную в Настройках.",
 		"status_downloading_update":                "Загрузка новой версии приложения...",
 		"download_update_error":                    "Не удалось загрузить обновление",
-		"update_download_complete_status":          "Обновление загружено успешно!",
-		"update_download_complete_title":           "Загрузка Завершена",
-		"update_download_complete_message":         "Новая версия сохранена в:\n%s\nПожалуйста, закройте это приложение и запустите новое.",
+		"update_confirm_title":                     "Обновление установщика",
+		"update_confirm_message":                   "Установщик загрузит новую версию, заменит себя и перезапустится. Продолжить?",
+		"status_installing_update":                 "Замена текущей программы...",
+		"status_restarting":                        "Запуск новой версии...",
+		"operation_warning_status":                 "Предупреждение: %v",
+		"livery_requires_newer_aircraft_title":     "Требуется обновление самолёта",
+		"livery_requires_newer_aircraft_message":   "Эти ливреи требуют более новой версии самолёта, чем установленная %s, и будут пропущены:\n- %s",
+		"livery_min_aircraft_line":                 "%s (требуется %s)",
+		"livery_requires_newer_aircraft_error":     "требуется версия самолёта %s или новее (установлена: %s)",
+		"update_unverified_error":                  "манифест версий не содержит контрольной суммы этой версии, её нельзя безопасно установить",
+		"update_unverified_confirm_message":        "Список версий не содержит контрольной суммы для этой версии, поэтому загрузку нельзя проверить. Устанавливайте её, только если доверяете источнику. Всё равно обновить?",
+		"download_unverified_status":               "Загрузка завершена, но контрольная сумма не опубликована, файл не проверен",
+		"aircraft_unverified_warning":              "Внимание: манифест версий не содержит контрольной суммы пакета самолёта, поэтому загруженный пакет не проверен.",
+		"aircraft_manifest_unavailable_warning":    "Предупреждение: не удалось загрузить список версий (%s), поэтому пакет самолёта не был проверен.",
+		"concurrency_adjusted_status":              "Число одновременных загрузок изменено на %d",
+		"update_install_error":                     "Не удалось установить обновление",
+		"update_rollback_error":                    "Новая версия не запустилась, предыдущая версия восстановлена",
 		"download_progress_label":                  "Загрузка... %.2f / %.2f МБ (%.2f МБ/с)",
 		"download_no_progress":                     "Предупреждение: Длина содержимого неизвестна. Прогресс не будет показан.",
 		"extract_progress_label":                   "Извлечение: %s",
 		"install_selected_liveries_button":         "Установить Выбранные Ливреи",
+		"livery_search_placeholder":                "Поиск по названию, авиакомпании, регистрации или коду ICAO",
+		"filter_all_types":                         "Реальные и вымышленные",
+		"filter_real":                              "Только реальные",
+		"filter_fictional":                         "Только вымышленные",
+		"filter_all_install_states":                "Установленные и нет",
+		"filter_installed":                         "Установленные",
+		"filter_not_installed":                     "Не установленные",
+		"filter_updates_only":                      "Только обновления",
+		"group_by_airline":                         "Группировать по авиакомпании",
+		"sort_by_name":                             "По названию",
+		"sort_by_airline":                          "По авиакомпании",
+		"sort_by_registration":                     "По регистрации",
+		"select_shown_button":                      "Выбрать показанные",
+		"clear_selection_button":                   "Снять выбор",
+		"catalog_count_label":                      "Показано %d из %d",
+		"livery_reconcile_error":                   "Не удалось проверить папки ливрей: %v",
+		"airline_group_label":                      "%s (%d)",
+		"no_airline_group":                         "Без авиакомпании",
+		"gallery_view_check":                       "Галерея",
+		"preview_none_label":                       "Нажмите на ливрею для предпросмотра",
+		"preview_airline_label":                    "Авиакомпания: %s",
+		"preview_registration_label":               "Регистрация: %s",
+		"preview_author_label":                     "Автор: %s",
+		"preview_real_label":                       "Реальная ливрея",
+		"preview_fictional_label":                  "Вымышленная ливрея",
+		"badge_installed":                          "Установлена",
+		"badge_outdated":                           "Есть обновление",
+		"badge_not_installed":                      "Не установлена",
+		"preview_version_label":                    "Версия: %s",
+		"preview_installed_label":                  "Установлена в: %s",
+		"preview_outdated_label":                   "Установлена, но в каталоге есть более новый пакет",
+		"preview_outdated_version_label":           "Установленная версия %s устарела",
+		"update_outdated_button":                   "Обновить устаревшие (%d)",
+		"update_outdated_title":                    "Обновление устаревших ливрей",
+		"update_outdated_confirm_message":          "Переустановить эти устаревшие ливреи (%d)?\n\n- %s",
+		"no_outdated_liveries_message":             "Все установленные ливреи актуальны.",
+		"uninstall_livery_entry":                   "%s (%s)",
 		"update_livery_list_button":                "Обновить Список Ливрей",
 		"uninstall_liveries_button":                "Удалить Ливреи...",
-		"status_updating_livery_list":              "Загрузка последнего списка ливрей...",
 		"livery_list_download_error":               "Не удалось загрузить список ливрей",
 		"livery_list_read_error":                   "Не удалось прочитать данные загруженного списка ливрей",
 		"livery_list_save_error":                   "Не удалось сохранить файл списка ливрей",
+		"catalog_state_save_error":                 "Список обновлён, но не удалось сохранить запись об обновлении",
 		"update_success_title":                     "Обновлено",
 		"livery_list_update_success":               "Список ливрей был успешно обновлён и перезагружен!",
+		"livery_list_not_modified":                 "Список ливрей уже актуален.",
+		"livery_list_invalid_error":                "Загруженный список ливрей некорректен, текущий список сохранён",
+		"livery_list_empty_error":                  "Загруженный список ливрей пуст, текущий список сохранён",
+		"catalog_diff_none":                        "Ливреи не добавлены, не удалены и не изменены.",
+		"catalog_diff_added":                       "Добавлено (%d):",
+		"catalog_diff_removed":                     "Удалено (%d):",
+		"catalog_diff_changed":                     "Изменено (%d):",
+		"catalog_diff_more":                        "… и ещё %d",
+		"catalog_last_refreshed_label":             "Список ливрей обновлён: %s",
+		"catalog_never_refreshed_label":            "Список ливрей ещё не обновлялся",
+		"status_updating_catalog_source":           "Обновление списка ливрей из %s...",
+		"catalog_source_failures":                  "Эти источники не удалось обновить, используется прежний список (%d):",
+		"preview_source_label":                     "Источник: %s",
+		"catalog_sources_label":                    "Источники списка ливрей",
+		"catalog_sources_hint":                     "Ливреи из всех включённых источников объединяются. Если одна ливрея есть в нескольких источниках, используется источник с наивысшим приоритетом. Новые удалённые источники загружаются при следующем обновлении списка ливрей.",
+		"catalog_source_add_button":                "Добавить источник",
+		"catalog_source_edit_title":                "Изменить источник",
+		"catalog_source_name_label":                "Название",
+		"catalog_source_location_label":            "Расположение",
+		"catalog_source_location_placeholder":      "https://example.com/liveries.json или локальный файл/папка",
+		"catalog_source_priority_label":            "Приоритет",
+		"catalog_source_enabled_check":             "Включён",
+		"catalog_source_info":                      "Приоритет %d · %s",
+		"catalog_source_delete_title":              "Удалить источник",
+		"catalog_source_delete_message":            "Удалить источник списка ливрей «%s»?",
+		"catalog_source_name_error":                "Введите название источника",
+		"catalog_source_duplicate_error":           "Источник с названием «%s» уже существует",
+		"catalog_source_https_error":               "Удалённые источники должны использовать ссылку https://",
+		"catalog_source_location_error":            "Файл или папка не найдены: %s",
+		"catalog_source_priority_error":            "Приоритет должен быть целым числом",
+		"catalog_source_last_error":                "Нужен хотя бы один источник списка ливрей",
 		"livery_list_load_fail":                    "Не удалось загрузить список ливрей. Попробуйте обновить его.",
 		"batch_download_progress_label":            "Загрузка (%d/%d): %s",
-		"batch_install_complete_status":            "%d ливрей обработано.",
+		"queue_title":                              "Очередь установки",
+		"queue_start_button":                       "Запустить очередь",
+		"queue_clear_finished_button":              "Убрать завершённые",
+		"queue_row_label":                          "%s (%s)",
+		"queue_pending_status":                     "В очереди ожидают установки ливрей: %d. Нажмите «Запустить очередь» на вкладке ливрей, чтобы продолжить.",
+		"job_state_queued":                         "ожидает",
+		"job_state_running":                        "установка",
+		"job_state_paused":                         "приостановлена",
+		"job_state_done":                           "установлена",
+		"job_state_failed":                         "ошибка",
+		"batch_install_report_status":              "Установлено ливрей: %d, ошибок: %d.",
+		"report_title":                             "Отчёт об установке",
+		"report_succeeded_label":                   "Установлено (%d):",
+		"report_failed_label":                      "Ошибки (%d):",
+		"queue_retry_failed_button":                "Повторить неудачные",
+		"close_button":                             "Закрыть",
 		"batch_install_complete_message":           "%d выбранных ливрей были обработаны и установлены.",
 		"scan_liveries_dir_error":                  "Не удалось сканировать каталог ливрей",
 		"no_installed_liveries_title":              "Ливреи не найдены",
@@ -1509,6 +2041,8 @@ var translations = map[string]map[string]string{
 		"uninstall_dialog_title":                   "Выберите Ливреи для Удаления",
 		"confirm_button":                           "Подтвердить",
 		"cancel_button":                            "Отмена",
+		"status_cancelling":                        "Отмена...",
+		"operation_cancelled_status":               "Отменено. Незавершённые загрузки и распакованные файлы удалены.",
 		"uninstall_final_confirm_title":            "Окончательное Удаление",
 		"uninstall_final_confirm_message":          "Вы уверены, что хотите окончательно удалить эти %d выбранных папок?\n\n- %s",
 		"uninstall_complete_title":                 "Отчёт об Удалении",
@@ -1525,6 +2059,35 @@ var translations = map[string]map[string]string{
 		"self_uninstall_warning":                   "ПРЕДУПРЕЖДЕНИЕ: Это удалит исполняемый файл установщика, а также его конфигурационные файлы и файлы списка ливрей. Вам нужно будет повторно загрузить приложение, чтобы использовать его снова.",
 		"self_uninstall_confirm_title":             "Подтвердить Удаление Приложения",
 		"self_uninstall_confirm_message":           "Вы уверены, что хотите полностью удалить это приложение и связанные с ним файлы с вашего компьютера?",
+		"download_retry_status":                    "Попытка %d/%d не удалась: %v. Повтор через %.0f с...",
+		"attempt_history_label":                    "История попыток:",
+		"attempt_history_line":                     "Попытка %d: %v",
+		"livery_download_failed_status":            "Не удалось загрузить %s: %v",
+		"integrity_sha256_mismatch_error":          "Проверка целостности не пройдена: SHA-256 загруженного файла не совпадает с опубликованным.\nОжидалось: %s\nПолучено: %s\nФайл удалён, ничего не установлено.",
+		"integrity_size_mismatch_error":            "Проверка целостности не пройдена: ожидалось %d байт, загружено %d байт.\nФайл удалён, ничего не установлено.",
+		"status_swapping_install":                  "Проверка и замена предыдущей установки...",
+		"install_failed_status":                    "Установка не удалась.",
+		"install_verify_error":                     "Не удалось установить новые файлы самолёта",
+		"install_rolled_back_message":              "Предыдущая установка восстановлена и не изменена.",
+		"verify_aircraft_button":                   "Проверить установленные файлы",
+		"verify_title":                             "Проверка файлов",
+		"verify_no_receipt_message":                "Запись об установке этого самолёта не найдена. Переустановите его один раз этой версией установщика, чтобы включить проверку.",
+		"verify_progress_label":                    "Проверка файлов... %d / %d",
+		"verify_ok_message":                        "Все %d установленных файлов на месте и не изменены.",
+		"verify_problems_message":                  "%d файлов отсутствуют или изменены:\n%s\n\nПереустановите самолёт, чтобы исправить их.",
+		"verify_missing_file":                      "Отсутствует: %s",
+		"verify_changed_file":                      "Изменён: %s",
+		"installed_version_label":                  "Установленная версия: %s",
+		"latest_version_label":                     "Последняя доступная версия: %s",
+		"published_on_label":                       "опубликована %s",
+		"version_unknown":                          "неизвестна",
+		"version_checking":                         "проверка...",
+		"version_unavailable":                      "не удалось проверить",
+		"update_available_label":                   "Доступно обновление самолёта.",
+		"up_to_date_label":                         "Установленный самолёт обновлён до последней версии.",
+		"update_aircraft_button":                   "Обновить AeroGennis A330-300",
+		"current_app_version_label":                "Текущая версия: %s",
+		"app_update_available_banner":              "Доступна новая версия установщика: %s (у вас %s).",
 	},
 }
 
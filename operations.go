@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"myapp/engine"
+)
+
+// 本文件把安装引擎的操作与界面连接起来，图形界面和命令行共用；引擎的进度事件经 eventSink 转换为当前语言的文字。
+
+// reporter 接收操作过程中的状态文字和进度（0 到 1）。
+type reporter interface {
+	Status(msg string)
+	Progress(fraction float64)
+}
+
+// eventReporter 是还想直接接收引擎事件的 reporter，例如命令行的 --json 模式。
+type eventReporter interface {
+	reporter
+	Event(e engine.Event)
+}
+
+// warningReporter 是需要把警告与普通进度区分开的 reporter，例如会丢弃过于频繁的状态行的命令行。
+type warningReporter interface {
+	reporter
+	warn(msg string)
+}
+
+// guiReporter 把进度显示在主窗口底部的状态栏和进度条上。
+type guiReporter struct {
+	state *AppState
+}
+
+func (r guiReporter) Status(msg string)         { r.state.statusLabel.SetText(msg) }
+func (r guiReporter) Progress(fraction float64) { r.state.progressBar.SetValue(fraction) }
+
+// channelReporter 供多线程安装使用，由一个 goroutine 统一更新界面；通道满时丢弃进度而不阻塞下载。
+type channelReporter struct {
+	statusUpdates   chan<- string
+	progressUpdates chan<- float64
+}
+
+func (r channelReporter) Status(msg string) {
+	select {
+	case r.statusUpdates <- msg:
+	default:
+	}
+}
+
+func (r channelReporter) Progress(fraction float64) {
+	select {
+	case r.progressUpdates <- fraction:
+	default:
+	}
+}
+
+// eventSink 返回把引擎事件显示到 rep 上的 Sink。
+func eventSink(state *AppState, rep reporter) engine.Sink {
+	raw, _ := rep.(eventReporter)
+	return func(e engine.Event) {
+		if raw != nil {
+			raw.Event(e)
+		}
+		if msg := describeEvent(state, e); msg != "" {
+			if w, ok := rep.(warningReporter); ok && e.Phase == engine.PhaseWarning {
+				w.warn(msg)
+			} else {
+				rep.Status(msg)
+			}
+		}
+		switch {
+		case e.Phase == engine.PhaseDownload && e.TotalBytes <= 0:
+			rep.Progress(0.5) // Indicate activity
+		case e.Fraction() >= 0:
+			rep.Progress(e.Fraction())
+		}
+	}
+}
+
+// describeEvent 返回事件对应的状态文字，不需要显示的事件返回空字符串。
+func describeEvent(state *AppState, e engine.Event) string {
+	switch e.Phase {
+	case engine.PhaseDownload:
+		if e.TotalBytes <= 0 {
+			return state.tr("download_no_progress")
+		}
+		return state.tr("download_progress_label", float64(e.Bytes)/(1024*1024), float64(e.TotalBytes)/(1024*1024), e.Speed)
+	case engine.PhaseRetry:
+		return state.tr("download_retry_status", e.Attempt, e.MaxAttempts, e.Err, e.Delay.Seconds())
+	case engine.PhaseExtract:
+		if e.File == "" {
+			return state.tr("status_extracting", e.Path)
+		}
+		return state.tr("extract_progress_label", e.File)
+	case engine.PhaseSwap:
+		return state.tr("status_swapping_install")
+	case engine.PhaseVerify:
+		if e.TotalFiles > 0 {
+			return state.tr("verify_progress_label", e.Files, e.TotalFiles)
+		}
+	case engine.PhaseReplace:
+		return state.tr("status_installing_update")
+	case engine.PhaseLaunch:
+		return state.tr("status_restarting")
+	case engine.PhaseUnverified:
+		return state.tr("download_unverified_status")
+	case engine.PhaseConcurrency:
+		return state.tr("concurrency_adjusted_status", e.Workers)
+	case engine.PhaseWarning:
+		return state.tr("operation_warning_status", e.Err)
+	}
+	return ""
+}
+
+// isCancelled 判断操作是否因为用户取消而结束。
+func isCancelled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// failedPhase 返回操作失败时所处的阶段。
+func failedPhase(err error) engine.Phase {
+	var opErr *engine.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Phase
+	}
+	return engine.PhaseError
+}
+
+// localizeError 把完整性错误转换为当前语言的说明，其余错误原样返回。
+func localizeError(state *AppState, err error) error {
+	var integrityErr *engine.IntegrityError
+	if !errors.As(err, &integrityErr) {
+		return err
+	}
+	if integrityErr.ExpectedSHA256 != "" && integrityErr.ActualSHA256 != "" {
+		return fmt.Errorf("%s", state.tr("integrity_sha256_mismatch_error", integrityErr.ExpectedSHA256, integrityErr.ActualSHA256))
+	}
+	return fmt.Errorf("%s", state.tr("integrity_size_mismatch_error", integrityErr.ExpectedSize, integrityErr.ActualSize))
+}
+
+// formatAttemptHistory 把尝试历史格式化为界面可显示的多行文本。
+func formatAttemptHistory(state *AppState, err error) string {
+	var retryErr *engine.RetryError
+	if !errors.As(err, &retryErr) || len(retryErr.Attempts) < 2 {
+		return ""
+	}
+	lines := make([]string, 0, len(retryErr.Attempts))
+	for _, a := range retryErr.Attempts {
+		lines = append(lines, state.tr("attempt_history_line", a.Attempt, a.Err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// withAttemptHistory 在错误后附加尝试历史，用于错误对话框。
+func withAttemptHistory(state *AppState, prefix string, err error) error {
+	if history := formatAttemptHistory(state, err); history != "" {
+		return fmt.Errorf("%s: %w\n\n%s\n%s", prefix, localizeError(state, err), state.tr("attempt_history_label"), history)
+	}
+	return fmt.Errorf("%s: %w", prefix, localizeError(state, err))
+}
+
+// aircraftInstallError 按失败的阶段给飞机安装的错误加上说明。
+func aircraftInstallError(state *AppState, err error) error {
+	switch failedPhase(err) {
+	case engine.PhasePrepare:
+		return fmt.Errorf("%s: %w", state.tr("temp_dir_error"), err)
+	case engine.PhaseDownload:
+		return withAttemptHistory(state, state.tr("download_error", state.tr("aircraft_package")), err)
+	case engine.PhaseExtract:
+		return fmt.Errorf("%s: %w", state.tr("extraction_error", state.tr("aircraft_package")), err)
+	}
+	var rollbackErr *engine.RollbackError
+	if errors.As(err, &rollbackErr) {
+		err = fmt.Errorf("%w\n\n%s", err, state.tr("install_rolled_back_message"))
+	}
+	return fmt.Errorf("%s: %w", state.tr("install_verify_error"), err)
+}
+
+// aircraftInstallResult 是飞机安装到一个配置档的结果。
+type aircraftInstallResult struct {
+	Profile string `json:"profile"`
+	Path    string `json:"path,omitempty"`
+	Version string `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+	// Unverified 表示版本清单没有公布飞机包的 SHA-256，安装的包未经校验
+	Unverified bool `json:"unverified,omitempty"`
+	// ManifestError 是无法下载版本清单的原因，这时飞机包同样未经校验
+	ManifestError string `json:"manifest_error,omitempty"`
+}
+
+// unverifiedWarning 返回飞机包未经校验时要告诉用户的警告，包已校验时返回空字符串。
+func unverifiedWarning(state *AppState, r aircraftInstallResult) string {
+	switch {
+	case r.ManifestError != "":
+		return state.tr("aircraft_manifest_unavailable_warning", r.ManifestError)
+	case r.Unverified:
+		return state.tr("aircraft_unverified_warning")
+	}
+	return ""
+}
+
+// installAircraftToProfiles 下载一次飞机包，然后依次安装到所选的每个配置档。
+// 下载失败时返回错误；单个配置档安装失败记录在结果中，不影响其它配置档。
+// 被取消时返回已完成的配置档的结果和 ctx.Err()，正在安装的配置档保持原样。
+// 只有全部配置档都安装成功后才删除下载的飞机包，否则保留在缓存中，重试时不必重新下载。
+func installAircraftToProfiles(ctx context.Context, state *AppState, profiles []string, rep reporter) ([]aircraftInstallResult, error) {
+	sink := eventSink(state, rep)
+	rep.Status(state.tr("status_creating_temp_dir"))
+	rep.Progress(0)
+	rep.Status(state.tr("status_downloading", state.tr("aircraft_package")))
+	src, manifestErr := aircraftSource(ctx)
+	if manifestErr != nil {
+		rep.Status(state.tr("aircraft_manifest_unavailable_warning", manifestErr))
+	}
+	pkg, err := engine.DownloadAircraft(ctx, src, sink)
+	if isCancelled(err) {
+		return nil, err
+	}
+	if err != nil {
+		rep.Status(state.tr("download_failed_status"))
+		return nil, aircraftInstallError(state, err)
+	}
+
+	var results []aircraftInstallResult
+	var cancelErr error
+	for _, name := range profiles {
+		if cancelErr = ctx.Err(); cancelErr != nil {
+			break
+		}
+		p := state.config.profile(name)
+		if p == nil {
+			continue
+		}
+		xpPath := p.XPlanePath
+		if name == state.config.ActiveProfile {
+			xpPath = state.xpPath
+		}
+		if len(profiles) > 1 {
+			rep.Status(state.tr("status_installing_for_profile", name))
+		}
+		result := aircraftInstallResult{Profile: name, Unverified: !pkg.Verified}
+		if manifestErr != nil {
+			result.ManifestError = manifestErr.Error()
+		}
+		target, version, err := engine.InstallAircraft(ctx, pkg, xpPath, sink)
+		if isCancelled(err) {
+			cancelErr = err
+			break
+		}
+		if err != nil {
+			result.Error = aircraftInstallError(state, err).Error()
+			results = append(results, result)
+			continue
+		}
+		result.Path, result.Version = target, version
+		results = append(results, result)
+		if name == state.config.ActiveProfile {
+			state.ag330Path = target
+		} else {
+			p.AG330Path = target
+		}
+	}
+	if cancelErr == nil && len(installFailures(results)) == 0 {
+		pkg.Remove()
+	}
+	writeConfig(state)
+	checkAircraftInstallation(state)
+	return results, cancelErr
+}
+
+// installFailures 返回安装失败的配置档的错误。
+func installFailures(results []aircraftInstallResult) []error {
+	var failures []error
+	for _, r := range results {
+		if r.Error == "" {
+			continue
+		}
+		if len(results) > 1 {
+			failures = append(failures, fmt.Errorf("%s: %s", r.Profile, r.Error))
+		} else {
+			failures = append(failures, errors.New(r.Error))
+		}
+	}
+	return failures
+}
+
+// profileLiveryDirs 返回所选配置档中已安装飞机的涂装目录。
+func profileLiveryDirs(state *AppState, profiles []string) []string {
+	var liveryDirs []string
+	for _, name := range profiles {
+		if p := state.config.profile(name); p != nil {
+			if aircraftPath, ok := profileAircraftPath(state, p); ok {
+				liveryDirs = append(liveryDirs, filepath.Join(aircraftPath, "liveries"))
+			}
+		}
+	}
+	return liveryDirs
+}
+
+// uninstallAircraft 删除当前配置档的飞机目录及其中所有收据。
+func uninstallAircraft(state *AppState) error {
+	if err := engine.UninstallAircraft(state.ag330Path); err != nil {
+		return err
+	}
+	state.isAircraftInstalled = false
+	state.ag330Path = ""
+	return writeConfig(state)
+}
+
+// updateLiveryList 刷新所有启用的远程来源（本地来源每次载入时直接读取），再重新合并目录。
+// 一个来源刷新失败时继续使用它上次的目录，结果中列出失败的来源；所有远程来源都失败时返回错误。
+func updateLiveryList(ctx context.Context, state *AppState, rep reporter) (*catalogRefresh, error) {
+	var failures []error
+	refreshed, changed := 0, 0
+	for _, s := range enabledSources(state.config) {
+		if !s.remote() {
+			continue
+		}
+		rep.Status(state.tr("status_updating_catalog_source", s.Name))
+		sourceChanged, stateErr, err := refreshSource(ctx, state, s, eventSink(state, rep))
+		if isCancelled(err) {
+			return nil, err
+		}
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", s.Name, err))
+			continue
+		}
+		if stateErr != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", s.Name, stateErr))
+		}
+		refreshed++
+		if sourceChanged {
+			changed++
+		}
+	}
+	if refreshed == 0 && len(failures) > 0 {
+		return nil, errors.Join(failures...)
+	}
+	liveries, err := loadCatalog(state.config)
+	if err != nil {
+		failures = append(failures, err)
+	}
+	diff := diffCatalogs(state.liveries, liveries)
+	return &catalogRefresh{
+		Liveries:    liveries,
+		Diff:        diff,
+		NotModified: changed == 0 && len(failures) == 0 && diff.empty(),
+		Failures:    failures,
+		RefreshedAt: time.Now(),
+	}, nil
+}
+
+// verifyAircraftInstall 按安装收据校验当前配置档的飞机文件；没有收据时返回 nil 收据。
+func verifyAircraftInstall(ctx context.Context, state *AppState, rep reporter) (*engine.Receipt, []engine.ReceiptProblem, error) {
+	return engine.VerifyAircraft(ctx, state.ag330Path, eventSink(state, rep))
+}
+
+// updateSource 返回要下载的新版本，带上版本清单中公布的 SHA-256 和大小。
+func updateSource(state *AppState) engine.Source {
+	return releaseSource(downloadURLUpdater[0], state.appUpdate)
+}
+
+// updateConfirmMessage 返回更新前的确认文字：版本清单没有公布校验值时警告用户新版本无法校验。
+func updateConfirmMessage(state *AppState) (msg string, unverified bool) {
+	if engine.NormalizeSHA256(updateSource(state).SHA256) == "" {
+		return state.tr("update_unverified_confirm_message"), true
+	}
+	return state.tr("update_confirm_message"), false
+}
+
+// selfUpdate 下载新版本、替换当前程序并启动新版本，新版本无法启动时恢复旧版本。
+// allowUnverified 表示用户已经确认安装没有公布校验值的版本。返回 nil 时新版本已经在运行，调用者应当退出。
+func selfUpdate(ctx context.Context, state *AppState, rep reporter, allowUnverified bool, launchArgs ...string) error {
+	rep.Progress(0)
+	rep.Status(state.tr("status_downloading_update"))
+	err := engine.SelfUpdate(ctx, updateSource(state), allowUnverified, eventSink(state, rep), launchArgs...)
+	if isCancelled(err) {
+		return err
+	}
+	switch failedPhase(err) {
+	case engine.PhasePrepare:
+		return err
+	case engine.PhaseDownload:
+		rep.Status(state.tr("download_failed_status"))
+		return withAttemptHistory(state, state.tr("download_update_error"), err)
+	case engine.PhaseVerify:
+		rep.Status(state.tr("download_failed_status"))
+		if errors.Is(err, engine.ErrUnverifiedUpdate) {
+			return fmt.Errorf("%s: %s", state.tr("download_update_error"), state.tr("update_unverified_error"))
+		}
+		return fmt.Errorf("%s: %w", state.tr("download_update_error"), err)
+	case engine.PhaseReplace:
+		rep.Status(state.tr("status_ready"))
+		return fmt.Errorf("%s: %w", state.tr("update_install_error"), err)
+	case engine.PhaseLaunch:
+		rep.Status(state.tr("status_ready"))
+		return fmt.Errorf("%s: %w", state.tr("update_rollback_error"), err)
+	}
+	return err
+}
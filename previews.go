@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"myapp/engine"
+)
+
+// previewWorkers 是同时下载的预览图数。
+const previewWorkers = 4
+
+// previewLoader 按需准备涂装的预览图：优先使用目录中的 preview 链接（经磁盘缓存下载），
+// 没有链接或下载失败时使用已安装涂装自带的图标。只有界面上显示到的涂装才会下载。
+type previewLoader struct {
+	cache *engine.PreviewCache // 打不开缓存目录时为 nil，只使用本地图标
+
+	mu       sync.Mutex
+	onLoaded func(id string)   // 后台准备好一张预览图后调用
+	local    map[string]string // 涂装 id → 已安装涂装自带的图标
+	paths    map[string]string // 涂装 id → 预览图文件，空字符串表示没有预览图
+	pending  map[string]bool
+	slots    chan struct{}
+}
+
+// newPreviewLoader 创建预览图加载器。打不开缓存目录时仍返回只使用本地图标的加载器，同时返回错误供界面提示。
+func newPreviewLoader() (*previewLoader, error) {
+	p := &previewLoader{local: make(map[string]string), paths: make(map[string]string), pending: make(map[string]bool), slots: make(chan struct{}, previewWorkers)}
+	cache, err := engine.NewPreviewCache()
+	p.cache = cache
+	return p, err
+}
+
+// setOnLoaded 在涂装页重建时更换完成回调。
+func (p *previewLoader) setOnLoaded(onLoaded func(id string)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onLoaded = onLoaded
+}
+
+// setLocal 更换已安装涂装的图标，并忘掉之前因为没有图而记下的空结果。
+func (p *previewLoader) setLocal(local map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.local = local
+	for id, path := range p.paths {
+		if path == "" {
+			delete(p.paths, id)
+		}
+	}
+}
+
+// path 返回涂装的预览图文件，ready 为 false 时正在后台准备，完成后调用 onLoaded。
+// 返回空字符串且 ready 为 true 表示这个涂装没有预览图。
+func (p *previewLoader) path(l Livery) (path string, ready bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if path, ok := p.paths[l.ID]; ok {
+		return path, true
+	}
+	local := p.local[l.ID]
+	if l.Preview == "" || p.cache == nil {
+		return local, true
+	}
+	if !p.pending[l.ID] {
+		p.pending[l.ID] = true
+		go p.fetch(l, local)
+	}
+	return "", false
+}
+
+// fetch 在后台下载预览图，失败时改用本地图标。失败不单独提示，目录中失效的预览链接很常见。
+func (p *previewLoader) fetch(l Livery, local string) {
+	p.slots <- struct{}{}
+	path, err := p.cache.Get(context.Background(), l.Preview)
+	<-p.slots
+	if err != nil {
+		path = local
+	}
+	p.mu.Lock()
+	p.paths[l.ID] = path
+	delete(p.pending, l.ID)
+	onLoaded := p.onLoaded
+	p.mu.Unlock()
+	if onLoaded != nil {
+		onLoaded(l.ID)
+	}
+}
+
+// localPreviews 返回按安装收据找到的已安装涂装自带的图标。
+func localPreviews(installs map[string]engine.LiveryInstall) map[string]string {
+	icons := make(map[string]string)
+	for id, install := range installs {
+		if install.Receipt == nil {
+			continue
+		}
+		if icon := engine.LocalPreview(install.Receipt); icon != "" {
+			icons[id] = icon
+		}
+	}
+	return icons
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"myapp/engine"
+)
+
+// profileAircraftPath 返回配置档中飞机的安装位置；当前配置档直接使用界面状态，因为它可能还没有写回配置。
+func profileAircraftPath(state *AppState, p *profileConfig) (string, bool) {
+	if p.Name == state.config.ActiveProfile {
+		return state.ag330Path, state.isAircraftInstalled
+	}
+	return engine.FindInstalledAircraft(p.XPlanePath, p.AG330Path)
+}
+
+// aircraftTargetProfiles 返回 X-Plane 路径有效、可以安装飞机的配置档。
+func aircraftTargetProfiles(state *AppState) []string {
+	var names []string
+	for i := range state.config.Profiles {
+		p := &state.config.Profiles[i]
+		if p.Name == state.config.ActiveProfile {
+			names = append(names, p.Name)
+			continue
+		}
+		if valid, _ := engine.ValidateXPlaneDir(p.XPlanePath); valid {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// liveryTargetProfiles 返回已经安装了飞机、可以安装涂装的配置档。
+func liveryTargetProfiles(state *AppState) []string {
+	var names []string
+	for i := range state.config.Profiles {
+		if _, ok := profileAircraftPath(state, &state.config.Profiles[i]); ok {
+			names = append(names, state.config.Profiles[i].Name)
+		}
+	}
+	return names
+}
+
+// chooseProfileTargets 在有多个可选配置档时让用户勾选要同时安装到哪些配置档，当前配置档默认勾选。
+// 只有一个可选时直接使用它。
+func chooseProfileTargets(state *AppState, eligible []string, onConfirm func([]string)) {
+	if len(eligible) <= 1 {
+		onConfirm(eligible)
+		return
+	}
+	targets := widget.NewCheckGroup(eligible, nil)
+	targets.SetSelected([]string{state.config.ActiveProfile})
+	content := container.NewVBox(widget.NewLabel(state.tr("install_targets_message")), targets)
+	dialog.ShowCustomConfirm(state.tr("install_targets_title"), state.tr("confirm_button"), state.tr("cancel_button"), content, func(confirm bool) {
+		if confirm && len(targets.Selected) > 0 {
+			onConfirm(targets.Selected)
+		}
+	}, state.mainWindow)
+}
+
+// switchProfile 切换到另一个配置档并重新显示界面。
+func switchProfile(state *AppState, name string) {
+	if name == state.config.ActiveProfile || state.config.profile(name) == nil {
+		return
+	}
+	if err := writeConfig(state); err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %w", state.tr("save_config_error"), err), state.mainWindow)
+		return
+	}
+	state.config.ActiveProfile = name
+	if err := state.config.save(); err != nil {
+		dialog.ShowError(fmt.Errorf("%s: %w", state.tr("save_config_error"), err), state.mainWindow)
+	}
+	showActiveProfile(state)
+}
+
+func handleNewProfile(state *AppState) {
+	nameEntry := widget.NewEntry()
+	items := []*widget.FormItem{widget.NewFormItem(state.tr("profile_name_label"), nameEntry)}
+	dialog.ShowForm(state.tr("new_profile_title"), state.tr("save_continue_button"), state.tr("cancel_button"), items, func(confirm bool) {
+		if !confirm {
+			return
+		}
+		// 新配置档沿用当前语言，X-Plane 路径在设置界面中选择
+		if err := state.config.addProfile(profileConfig{Name: nameEntry.Text, Language: state.language}); err != nil {
+			dialog.ShowError(fmt.Errorf("%s: %w", state.tr("profile_name_error"), err), state.mainWindow)
+			return
+		}
+		switchProfile(state, state.config.Profiles[len(state.config.Profiles)-1].Name)
+	}, state.mainWindow)
+}
+
+func handleDeleteProfile(state *AppState) {
+	name := state.config.ActiveProfile
+	dialog.ShowConfirm(state.tr("delete_profile_confirm_title"), state.tr("delete_profile_confirm_message", name), func(confirm bool) {
+		if !confirm {
+			return
+		}
+		if err := state.config.removeProfile(name); err != nil {
+			dialog.ShowError(err, state.mainWindow)
+			return
+		}
+		if err := state.config.save(); err != nil {
+			dialog.ShowError(fmt.Errorf("%s: %w", state.tr("save_config_error"), err), state.mainWindow)
+		}
+		showActiveProfile(state)
+	}, state.mainWindow)
+}
+
+// createProfileSection 是设置页面中的配置档切换器。
+func createProfileSection(state *AppState) fyne.CanvasObject {
+	var names []string
+	for _, p := range state.config.Profiles {
+		names = append(names, p.Name)
+	}
+	profileSelect := widget.NewSelect(names, nil)
+	profileSelect.SetSelected(state.config.ActiveProfile)
+	profileSelect.OnChanged = func(name string) { switchProfile(state, name) }
+	newProfileBtn := widget.NewButton(state.tr("new_profile_button"), func() { handleNewProfile(state) })
+	deleteProfileBtn := widget.NewButton(state.tr("delete_profile_button"), func() { handleDeleteProfile(state) })
+	if len(state.config.Profiles) <= 1 {
+		deleteProfileBtn.Disable()
+	}
+	return container.NewVBox(
+		widget.NewLabelWithStyle(state.tr("profiles_label"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		profileSelect,
+		container.NewGridWithColumns(2, newProfileBtn, deleteProfileBtn),
+	)
+}